@@ -0,0 +1,122 @@
+// Package changelog renders the commits going into a release, either for
+// display on screen or as the markdown body of the GitHub release notes.
+package changelog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/plan"
+)
+
+// RenderScreen formats a CommitsComparison suitable for displaying on the
+// screen to the user, abbreviated to try to not overflow a 80x24 terminal.
+//
+// Because of this, we only display the 10 most recent commits, with a
+// comparison URL targeting HEAD (as draft is not released), so user can view
+// the full list on GitHub if desired.
+func RenderScreen(comparison *github.CommitsComparison) string {
+	return RenderScreenWith(comparison, Options{})
+}
+
+// screenReservedLines is how many lines of the terminal to leave for
+// everything else on screen alongside the commits: the latest release and
+// the headers and footers of the changelog above, and the version prompt
+// below. This leaves the 10 commits of RenderScreen in a 24 line terminal.
+const screenReservedLines = 14
+
+// RenderScreenWith is RenderScreen with the optional parts set in opts, which
+// can fit the changelog to the actual size of the terminal.
+func RenderScreenWith(comparison *github.CommitsComparison, opts Options) string {
+	maxDisplayCommits := 10
+	if opts.Height > 0 {
+		maxDisplayCommits = max(3, opts.Height-screenReservedLines)
+	}
+	var buf strings.Builder
+	buf.WriteString("Changes since previous release:\n\n")
+
+	const bullet = "  - "
+	for _, c := range comparison.Commits[:min(maxDisplayCommits, len(comparison.Commits))] {
+		fmt.Fprintf(&buf, "%s%v\n", bullet, screenSubject(firstCommitMsgLine(c), opts.Width-len(bullet), opts))
+	}
+
+	if numExtraCommits := len(comparison.Commits) - maxDisplayCommits; numExtraCommits > 0 {
+		fmt.Fprintf(&buf, "\n...%d more commits, %s\n", numExtraCommits, comparison.GetHTMLURL())
+	}
+
+	if len(opts.Hidden) > 0 {
+		var total int
+		reasons := make([]string, len(opts.Hidden))
+		for i, h := range opts.Hidden {
+			total += h.Count
+			reasons[i] = fmt.Sprintf("%d %s", h.Count, h.Reason)
+		}
+		fmt.Fprintf(&buf, "\n(%d commits hidden: %s)\n", total, strings.Join(reasons, ", "))
+	}
+
+	return buf.String()
+}
+
+// RenderMarkdown formats a CommitsComparison suitable for markdown display in
+// a GitHub Flavored Markdown release notes field.
+//
+// TODO: cap max number of commits to display? API returns <=250
+func RenderMarkdown(comparison *github.CommitsComparison) string {
+	return RenderMarkdownWith(comparison, Options{})
+}
+
+// Options controls optional parts of the rendered changelog.
+type Options struct {
+	// Authors credits the authors and co-authors of each commit.
+	Authors bool
+	// Contributors adds a section thanking everyone who contributed.
+	Contributors bool
+	// FirstTime lists contributors to welcome in a section of their own, as
+	// their first contribution is in the release.
+	FirstTime []Author
+	// Hidden are the commits hidden by a Filter, to be noted on screen.
+	Hidden []Hidden
+	// Links, if set, turns issue references and commit SHAs into links, as
+	// markdown or as terminal hyperlinks on screen.
+	Links *Links
+	// Width and Height are the size of the terminal to fit the changelog on
+	// screen to. Subjects are not truncated if Width is zero, and 10 commits
+	// are shown if Height is zero.
+	Width, Height int
+	// Color colors the types of conventional commits on screen.
+	Color bool
+}
+
+// RenderMarkdownWith is RenderMarkdown with the optional parts set in opts.
+func RenderMarkdownWith(comparison *github.CommitsComparison, opts Options) string {
+	var buf strings.Builder
+	buf.WriteString("## Changelog\n\n")
+
+	contributors := Contributors(comparison)
+	for _, c := range comparison.Commits {
+		fmt.Fprintf(&buf, "- %v %v", opts.Links.markdown(firstCommitMsgLine(c)), opts.Links.commit(c.GetSHA(), markdownLink))
+		if opts.Authors {
+			fmt.Fprintf(&buf, " by %s", joinAuthors(CommitAuthors(c), contributors))
+		}
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString(renderCredits(contributors, opts))
+	return buf.String()
+}
+
+func firstCommitMsgLine(c github.RepositoryCommit) string {
+	msg := c.Commit.GetMessage()
+	lines := strings.SplitN(msg, "\n", 2)
+	return lines[0]
+}
+
+// CompareURL makes a GitHub web view URL for comparing two tagged semvers.
+func CompareURL(owner, repo string, base, next *semver.Version) string {
+	return fmt.Sprintf(
+		"https://github.com/%s/%s/compare/v%s...v%s",
+		owner, repo, plan.VersionString(base), plan.VersionString(next),
+	)
+}
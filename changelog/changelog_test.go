@@ -0,0 +1,378 @@
+package changelog
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/internal/bumptest"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestRenderScreen(t *testing.T) {
+	for name, comparison := range bumptest.CommitsComparisons {
+		t.Run(name, func(t *testing.T) {
+			got := RenderScreen(comparison)
+
+			goldenFile := filepath.Join("testdata", name+"_screen.golden")
+
+			if *update {
+				err := os.WriteFile(goldenFile, []byte(got), 0644)
+				if err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+				return
+			}
+
+			wantBytes, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("failed to read golden file %s: %v", goldenFile, err)
+			}
+			want := string(wantBytes)
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("RenderScreen() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	for name, comparison := range bumptest.CommitsComparisons {
+		t.Run(name, func(t *testing.T) {
+			got := RenderMarkdown(comparison)
+
+			goldenFile := filepath.Join("testdata", name+"_markdown.golden")
+
+			if *update {
+				err := os.WriteFile(goldenFile, []byte(got), 0644)
+				if err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+				return
+			}
+
+			wantBytes, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("failed to read golden file %s: %v", goldenFile, err)
+			}
+			want := string(wantBytes)
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("RenderMarkdown() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownCredits(t *testing.T) {
+	opts := Options{
+		Authors:      true,
+		Contributors: true,
+		FirstTime:    []Author{{Email: "12345+danalee@users.noreply.github.com"}},
+	}
+	got := RenderMarkdownWith(bumptest.CommitsComparisons["sample"], opts)
+
+	goldenFile := filepath.Join("testdata", "sample_credits.golden")
+	if *update {
+		if err := os.WriteFile(goldenFile, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s: %v", goldenFile, err)
+	}
+	if diff := cmp.Diff(string(want), got); diff != "" {
+		t.Errorf("RenderMarkdownWith() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCommitAuthors(t *testing.T) {
+	c := bumptest.CommitsComparisons["sample"].Commits[1]
+	got := CommitAuthors(c)
+	want := []Author{
+		{Login: "bobsmith", Name: "Bob Smith", Email: "bob@example.com"},
+		{Login: "danalee", Name: "Dana Lee", Email: "12345+danalee@users.noreply.github.com"},
+		{Name: "Alice Johnson", Email: "alice@example.com"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CommitAuthors() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	commit := func(msg, login string, parents int, files ...string) github.RepositoryCommit {
+		c := github.RepositoryCommit{
+			SHA:     github.String(msg),
+			Commit:  &github.Commit{Message: github.String(msg)},
+			Author:  &github.User{Login: github.String(login)},
+			Parents: make([]github.Commit, parents),
+		}
+		for _, f := range files {
+			c.Files = append(c.Files, github.CommitFile{Filename: github.String(f)})
+		}
+		return c
+	}
+	comparison := &github.CommitsComparison{Commits: []github.RepositoryCommit{
+		commit("feat: add --foo", "alice", 1, "main.go", "README.md"),
+		commit("Merge pull request #12 from bob/fix", "bob", 2),
+		commit("build(deps): bump go-git to v5.19.1", "dependabot[bot]", 1),
+		commit("wip", "bob", 1),
+		commit("chore(release): v1.2.0", "alice", 1),
+		commit("docs: fix typo", "bob", 1, "docs/usage.md", "README.md"),
+		commit("fix: parse dates", "bob", 1, "plan/plan.go"),
+	}}
+	shas := func(c *github.CommitsComparison) []string {
+		var s []string
+		for _, c := range c.Commits {
+			s = append(s, c.GetSHA())
+		}
+		return s
+	}
+
+	tests := []struct {
+		name       string
+		filter     Filter
+		wantSHAs   []string
+		wantHidden []Hidden
+	}{
+		{"zero value", Filter{}, shas(comparison), nil},
+		{
+			"exclude",
+			Filter{Exclude: []Rule{
+				{Merges: true},
+				{Authors: []string{"*[bot]"}, Reason: "dependency bumps"},
+				{Subject: `^(?i)wip\b`},
+				{Types: []string{"chore"}},
+				{Paths: []string{"docs/", "*.md"}},
+			}},
+			[]string{"feat: add --foo", "fix: parse dates"},
+			[]Hidden{
+				{"merge commits", 1},
+				{"dependency bumps", 1},
+				{`matching "^(?i)wip\\b"`, 1},
+				{"chore commits", 1},
+				{"only touching docs/, *.md", 1},
+			},
+		},
+		{
+			"include",
+			Filter{
+				Include: []Rule{{Types: []string{"feat", "fix"}}},
+				Exclude: []Rule{{Authors: []string{"alice"}}},
+			},
+			[]string{"fix: parse dates"},
+			[]Hidden{{"by alice", 1}, {"not included", 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hidden, err := tt.filter.Apply(comparison)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.wantSHAs, shas(got)); diff != "" {
+				t.Errorf("Apply() commits mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantHidden, hidden); diff != "" {
+				t.Errorf("Apply() hidden mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	for _, bad := range []Filter{
+		{Exclude: []Rule{{Subject: "("}}},
+		{Exclude: []Rule{{Reason: "no criteria"}}},
+		{Include: []Rule{{}}},
+	} {
+		if _, _, err := bad.Apply(comparison); err == nil {
+			t.Errorf("Apply() with %+v succeeded, want error", bad)
+		}
+	}
+}
+
+func TestRenderScreenHidden(t *testing.T) {
+	opts := Options{Hidden: []Hidden{{"merge commits", 2}, {"by *[bot]", 1}}}
+	got := RenderScreenWith(bumptest.CommitsComparisons["sample"], opts)
+	want := "\n(3 commits hidden: 2 merge commits, 1 by *[bot])\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("RenderScreenWith() = %q, want suffix %q", got, want)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	links := &Links{
+		Owner: "owner",
+		Repo:  "repo",
+		Trackers: []Tracker{
+			{Pattern: `\bPROJ-(\d+)\b`, URL: "https://example.atlassian.net/browse/PROJ-$1"},
+			{Pattern: `GH-\d+`, URL: "https://example.com/never"},
+		},
+	}
+	tests := []struct {
+		subject string
+		want    string
+	}{
+		{"fix login crash (#412)", "fix login crash ([#412](https://github.com/owner/repo/issues/412))"},
+		{"fix GH-7 and #8", "fix [GH-7](https://github.com/owner/repo/issues/7) and [#8](https://github.com/owner/repo/issues/8)"},
+		{"closes PROJ-88", "closes [PROJ-88](https://example.atlassian.net/browse/PROJ-88)"},
+		{"keep abc#1, &#39; and #x as is", "keep abc#1, &#39; and #x as is"},
+		{"no references", "no references"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			if got := links.markdown(tt.subject); got != tt.want {
+				t.Errorf("markdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var none *Links
+	if got := none.markdown("fix #1"); got != "fix #1" {
+		t.Errorf("nil markdown() = %q, want it unchanged", got)
+	}
+
+	comparison := &github.CommitsComparison{Commits: []github.RepositoryCommit{{
+		SHA:    github.String("a1b2c3d4e5f6789012345678901234567890abcd"),
+		Commit: &github.Commit{Message: github.String("fix crash (#412)")},
+	}}}
+	opts := Options{Links: &Links{Owner: "owner", Repo: "repo"}}
+	wantMarkdown := "- fix crash ([#412](https://github.com/owner/repo/issues/412)) " +
+		"[a1b2c3d](https://github.com/owner/repo/commit/a1b2c3d4e5f6789012345678901234567890abcd)\n"
+	if got := RenderMarkdownWith(comparison, opts); !strings.Contains(got, wantMarkdown) {
+		t.Errorf("RenderMarkdownWith() = %q, want it to contain %q", got, wantMarkdown)
+	}
+	wantScreen := "  - fix crash (\x1b]8;;https://github.com/owner/repo/issues/412\x1b\\#412\x1b]8;;\x1b\\)\n"
+	if got := RenderScreenWith(comparison, opts); !strings.Contains(got, wantScreen) {
+		t.Errorf("RenderScreenWith() = %q, want it to contain %q", got, wantScreen)
+	}
+}
+
+func TestRenderScreenTerminal(t *testing.T) {
+	comparison := &github.CommitsComparison{Commits: []github.RepositoryCommit{
+		{Commit: &github.Commit{Message: github.String("feat(cli): add --foo (#12)")}},
+		{Commit: &github.Commit{Message: github.String("fix!: drop support for the old config format entirely")}},
+		{Commit: &github.Commit{Message: github.String("chore: tidy up")}},
+		{Commit: &github.Commit{Message: github.String("update README")}},
+		{Commit: &github.Commit{Message: github.String("fix: crash on start, reported in #1234")}},
+	}}
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			"plain",
+			Options{},
+			[]string{
+				"  - feat(cli): add --foo (#12)\n",
+				"  - fix!: drop support for the old config format entirely\n",
+				"  - fix: crash on start, reported in #1234\n",
+			},
+		},
+		{
+			"truncated",
+			Options{Width: 40, Height: 17},
+			[]string{
+				"  - fix!: drop support for the old conf…\n",
+				"  - chore: tidy up\n",
+				"\n...2 more commits",
+			},
+		},
+		{
+			"colored",
+			Options{Color: true},
+			[]string{
+				"  - \x1b[32mfeat(cli)\x1b[0m: add --foo (#12)\n",
+				"  - \x1b[1;31mfix!\x1b[0m: drop support",
+				"  - \x1b[2mchore\x1b[0m: tidy up\n",
+				"  - update README\n",
+			},
+		},
+		{
+			"truncated links",
+			Options{Links: &Links{Owner: "owner", Repo: "repo"}, Width: 40},
+			[]string{
+				"(\x1b]8;;https://github.com/owner/repo/issues/12\x1b\\#12\x1b]8;;\x1b\\)\n",
+				"  - fix: crash on start, reported in #1…\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderScreenWith(comparison, tt.opts)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("RenderScreenWith() = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateFile(t *testing.T) {
+	rel := FileRelease{
+		Owner:      "owner",
+		Repo:       "repo",
+		Previous:   semver.MustParse("1.0.0"),
+		Version:    semver.MustParse("1.1.0"),
+		Date:       bumptest.Now,
+		Comparison: bumptest.CommitsComparisons["sample"],
+	}
+
+	for _, name := range []string{"keepachangelog", "keepachangelog_new"} {
+		t.Run(name, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", name+".input"))
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			got := UpdateFile(string(input), rel)
+
+			goldenFile := filepath.Join("testdata", name+".golden")
+
+			if *update {
+				err := os.WriteFile(goldenFile, []byte(got), 0644)
+				if err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+				return
+			}
+
+			wantBytes, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("failed to read golden file %s: %v", goldenFile, err)
+			}
+			want := string(wantBytes)
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("UpdateFile() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChangeType(t *testing.T) {
+	testCases := map[string]string{
+		"feat: add --foo":                "Added",
+		"Feat: add --foo":                "Added",
+		"feat(cli)!: drop --bar":         "Added",
+		"fix(parser): handle dates":      "Fixed",
+		"FIX: handle dates":              "Fixed",
+		"fixed the build":                "Changed",
+		"feature: not conventional":      "Changed",
+		"docs: explain --foo\n\nfeat: x": "Changed",
+	}
+	for msg, want := range testCases {
+		if got := changeType(msg); got != want {
+			t.Errorf("changeType(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
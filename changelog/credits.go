@@ -0,0 +1,127 @@
+package changelog
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v29/github"
+)
+
+// Author is someone credited with a commit, either as its author or as a
+// co-author.
+type Author struct {
+	Login string // GitHub login, if known
+	Name  string
+	Email string
+}
+
+// String returns the @login of the author, or their name if the login is not
+// known.
+func (a Author) String() string {
+	if a.Login != "" {
+		return "@" + a.Login
+	}
+	return cmp.Or(a.Name, a.Email)
+}
+
+// Same reports whether a and b are the same person, going by their logins if
+// both are known, otherwise their email addresses or names.
+func (a Author) Same(b Author) bool {
+	if a.Login != "" && b.Login != "" {
+		return strings.EqualFold(a.Login, b.Login)
+	}
+	if a.Email != "" && b.Email != "" {
+		return strings.EqualFold(a.Email, b.Email)
+	}
+	return a.Name == b.Name
+}
+
+// coAuthorPattern matches a Co-authored-by trailer in a commit message.
+var coAuthorPattern = regexp.MustCompile(`(?im)^co-authored-by:\s*(.*?)\s*<([^>]+)>\s*$`)
+
+// noreplyPattern matches the noreply email addresses GitHub gives its users,
+// capturing the login.
+var noreplyPattern = regexp.MustCompile(`(?i)^(?:\d+\+)?([a-z0-9-]+)@users\.noreply\.github\.com$`)
+
+// CommitAuthors returns the author of c, followed by any co-authors named in
+// Co-authored-by trailers of its message.
+func CommitAuthors(c github.RepositoryCommit) []Author {
+	author := Author{
+		Login: c.GetAuthor().GetLogin(),
+		Name:  c.GetCommit().GetAuthor().GetName(),
+		Email: c.GetCommit().GetAuthor().GetEmail(),
+	}
+	authors := []Author{author}
+	for _, m := range coAuthorPattern.FindAllStringSubmatch(c.GetCommit().GetMessage(), -1) {
+		a := Author{Name: m[1], Email: m[2]}
+		if l := noreplyPattern.FindStringSubmatch(a.Email); l != nil {
+			a.Login = l[1]
+		}
+		if !slices.ContainsFunc(authors, a.Same) {
+			authors = append(authors, a)
+		}
+	}
+	return authors
+}
+
+// Contributors returns everyone credited with the commits in comparison, once
+// each, in alphabetical order. The login of a contributor is filled in from
+// any of their commits it is known for.
+func Contributors(comparison *github.CommitsComparison) []Author {
+	var all []Author
+	for _, c := range comparison.Commits {
+		for _, a := range CommitAuthors(c) {
+			if i := slices.IndexFunc(all, a.Same); i == -1 {
+				all = append(all, a)
+			} else if all[i].Login == "" {
+				all[i].Login = a.Login
+			}
+		}
+	}
+	slices.SortFunc(all, func(a, b Author) int {
+		return strings.Compare(strings.ToLower(a.String()), strings.ToLower(b.String()))
+	})
+	return all
+}
+
+// joinAuthors lists authors in prose, e.g. "@alice, @bob and Carol", as
+// known among contributors.
+func joinAuthors(authors, contributors []Author) string {
+	names := make([]string, len(authors))
+	for i, a := range authors {
+		if j := slices.IndexFunc(contributors, a.Same); j != -1 {
+			a = contributors[j]
+		}
+		names[i] = a.String()
+	}
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return fmt.Sprintf("%s and %s", strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
+// renderCredits formats the Contributors and New contributors sections of
+// the markdown release notes, as enabled in opts.
+func renderCredits(contributors []Author, opts Options) string {
+	var buf strings.Builder
+	if opts.Contributors && len(contributors) > 0 {
+		fmt.Fprintf(&buf, "\n## Contributors\n\nThanks to %s!\n", joinAuthors(contributors, contributors))
+	}
+
+	var firstTimers []Author
+	for _, a := range contributors {
+		if slices.ContainsFunc(opts.FirstTime, a.Same) {
+			firstTimers = append(firstTimers, a)
+		}
+	}
+	if len(firstTimers) > 0 {
+		buf.WriteString("\n## New contributors\n\n")
+		for _, a := range firstTimers {
+			fmt.Fprintf(&buf, "- %s made their first contribution 🎉\n", a)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,32 @@
+package changelog_test
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/changelog"
+)
+
+func ExampleRenderMarkdown() {
+	comparison := &github.CommitsComparison{
+		Commits: []github.RepositoryCommit{
+			{
+				SHA:    github.String("a1b2c3d4e5f6789012345678901234567890abcd"),
+				Commit: &github.Commit{Message: github.String("feat: add webhooks\n\nLonger description.")},
+			},
+			{
+				SHA:    github.String("b2c3d4e5f6789012345678901234567890abcdef"),
+				Commit: &github.Commit{Message: github.String("fix: handle empty payloads")},
+			},
+		},
+	}
+	fmt.Print(changelog.RenderMarkdown(comparison))
+	fmt.Print(changelog.CompareURL("mroth", "bump", semver.MustParse("1.0.0"), semver.MustParse("1.1.0")))
+	// Output:
+	// ## Changelog
+	//
+	// - feat: add webhooks a1b2c3d
+	// - fix: handle empty payloads b2c3d4e
+	// https://github.com/mroth/bump/compare/v1.0.0...v1.1.0
+}
@@ -0,0 +1,187 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/plan"
+)
+
+// FileHeader is the introduction used when creating a new changelog file.
+const FileHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// FileRelease describes a release to be recorded in a changelog file.
+type FileRelease struct {
+	Owner, Repo string
+	Previous    *semver.Version
+	Version     *semver.Version
+	Date        time.Time
+	Comparison  *github.CommitsComparison
+}
+
+// keepAChangelogTypes are the change types defined by Keep a Changelog, in the
+// order they should appear within a release section.
+var keepAChangelogTypes = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+var (
+	releaseHeadingPattern = regexp.MustCompile(`^## \[`)
+	unreleasedPattern     = regexp.MustCompile(`(?i)^## \[?unreleased\]?\s*$`)
+	linkRefPattern        = regexp.MustCompile(`^\[([^\]]+)\]:\s*\S+`)
+)
+
+// UpdateFile adds a section for a new release to the contents of a changelog
+// file in the Keep a Changelog format (https://keepachangelog.com), returning
+// the updated contents. An empty doc is treated as a new file.
+//
+// The section is inserted above the previous release, and contains any entries
+// which were listed under the Unreleased heading followed by the commits in
+// the release, grouped by change type. The link references at the bottom of
+// the file are updated to point at the new release.
+func UpdateFile(doc string, rel FileRelease) string {
+	if strings.TrimSpace(doc) == "" {
+		doc = FileHeader
+	}
+	lines := strings.Split(strings.TrimRight(doc, "\n"), "\n")
+
+	// split off the trailing block of link references, if any
+	refStart := len(lines)
+	for i := len(lines) - 1; i >= 0; i-- {
+		if linkRefPattern.MatchString(lines[i]) {
+			refStart = i
+		} else if strings.TrimSpace(lines[i]) != "" {
+			break
+		}
+	}
+	refs := lines[refStart:]
+	lines = lines[:refStart]
+
+	// find the existing Unreleased section, if any, which runs until the
+	// heading of the previous release.
+	unreleasedStart := slices.IndexFunc(lines, unreleasedPattern.MatchString)
+	prevStart := len(lines)
+	for i := unreleasedStart + 1; i < len(lines); i++ {
+		if releaseHeadingPattern.MatchString(lines[i]) {
+			prevStart = i
+			break
+		}
+	}
+
+	var before, unreleased, after []string
+	if unreleasedStart == -1 {
+		before = lines[:prevStart]
+	} else {
+		before = lines[:unreleasedStart]
+		unreleased = lines[unreleasedStart+1 : prevStart]
+	}
+	after = lines[prevStart:]
+
+	var buf strings.Builder
+	for _, line := range trimBlankLines(before) {
+		buf.WriteString(line + "\n")
+	}
+	buf.WriteString("\n## [Unreleased]\n\n")
+	fmt.Fprintf(&buf, "## [%s] - %s\n\n", plan.VersionString(rel.Version), rel.Date.Format(time.DateOnly))
+	buf.WriteString(renderFileSection(unreleased, rel.Comparison))
+	if after = trimBlankLines(after); len(after) > 0 {
+		buf.WriteString("\n")
+		for _, line := range after {
+			buf.WriteString(line + "\n")
+		}
+	}
+	buf.WriteString("\n")
+	for _, line := range updateLinkRefs(trimBlankLines(refs), rel) {
+		buf.WriteString(line + "\n")
+	}
+	return buf.String()
+}
+
+// renderFileSection merges the entries moved from the Unreleased section with
+// the commits in comparison, grouped under Keep a Changelog change types.
+func renderFileSection(unreleased []string, comparison *github.CommitsComparison) string {
+	order := slices.Clone(keepAChangelogTypes)
+	groups := make(map[string][]string)
+
+	// entries before any type heading are kept at the top of the section
+	var current string
+	for _, line := range unreleased {
+		if name, ok := strings.CutPrefix(line, "### "); ok {
+			current = strings.TrimSpace(name)
+			if !slices.Contains(order, current) {
+				order = append(order, current)
+			}
+			continue
+		}
+		groups[current] = append(groups[current], line)
+	}
+	for name, entries := range groups {
+		groups[name] = trimBlankLines(entries)
+	}
+
+	for _, c := range comparison.Commits {
+		name := changeType(c.GetCommit().GetMessage())
+		groups[name] = append(groups[name], "- "+firstCommitMsgLine(c))
+	}
+
+	var buf strings.Builder
+	if loose := groups[""]; len(loose) > 0 {
+		buf.WriteString(strings.Join(loose, "\n") + "\n\n")
+	}
+	for _, name := range order {
+		if entries := groups[name]; len(entries) > 0 {
+			fmt.Fprintf(&buf, "### %s\n\n%s\n\n", name, strings.Join(entries, "\n"))
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// changeType maps a commit message to a Keep a Changelog change type, based on
+// its conventional commit type if it has one, as parsed for the suggested
+// increment.
+func changeType(msg string) string {
+	switch typ, _, _ := plan.ParseConventional(msg); typ {
+	case "feat":
+		return "Added"
+	case "fix":
+		return "Fixed"
+	default:
+		return "Changed"
+	}
+}
+
+// updateLinkRefs points the Unreleased link reference at changes since the
+// new release, and adds a reference for the new release's heading.
+func updateLinkRefs(refs []string, rel FileRelease) []string {
+	version := plan.VersionString(rel.Version)
+	unreleasedRef := fmt.Sprintf("[unreleased]: https://github.com/%s/%s/compare/v%s...HEAD", rel.Owner, rel.Repo, version)
+	versionRef := fmt.Sprintf("[%s]: %s", version, CompareURL(rel.Owner, rel.Repo, rel.Previous, rel.Version))
+
+	i := slices.IndexFunc(refs, func(line string) bool {
+		m := linkRefPattern.FindStringSubmatch(line)
+		return m != nil && strings.EqualFold(m[1], "unreleased")
+	})
+	if i == -1 {
+		return append([]string{unreleasedRef, versionRef}, refs...)
+	}
+	return slices.Insert(slices.Replace(slices.Clone(refs), i, i+1, unreleasedRef), i+1, versionRef)
+}
+
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
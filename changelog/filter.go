@@ -0,0 +1,209 @@
+package changelog
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/plan"
+)
+
+// Filter decides which commits are listed in the changelog, hiding noise
+// such as merge commits or dependency bumps by bots.
+//
+// The zero value keeps every commit.
+type Filter struct {
+	// Include, if set, keeps only the commits matching at least one rule.
+	Include []Rule `json:"include,omitempty"`
+	// Exclude hides the commits matching any rule, even if included.
+	Exclude []Rule `json:"exclude,omitempty"`
+}
+
+// Rule matches commits meeting all of its criteria which are set.
+type Rule struct {
+	// Subject is a regular expression matched against the first line of
+	// the commit message.
+	Subject string `json:"subject,omitempty"`
+	// Authors are the GitHub logins (or names, if the login is unknown) of
+	// commit authors, in which * matches anything, e.g. "*[bot]".
+	Authors []string `json:"authors,omitempty"`
+	// Types are Conventional Commits types, such as "chore".
+	Types []string `json:"types,omitempty"`
+	// Merges matches merge commits.
+	Merges bool `json:"merges,omitempty"`
+	// Paths matches commits which only touch files matching these paths,
+	// either globs such as "*.md" or directories such as "docs/".
+	Paths []string `json:"paths,omitempty"`
+	// Reason describes the commits matched, for saying why commits were
+	// hidden. If empty, one is made up from the criteria.
+	Reason string `json:"reason,omitempty"`
+
+	// subject and authors are compiled from Subject and Authors by Validate.
+	subject *regexp.Regexp
+	authors []*regexp.Regexp
+}
+
+// Hidden counts the commits hidden by a Filter for one reason.
+type Hidden struct {
+	Reason string
+	Count  int
+}
+
+// Validate checks that every rule has some criteria, as one without would
+// match every commit, and that their patterns compile, compiling them for
+// matching commits.
+func (f *Filter) Validate() error {
+	for _, rules := range [][]Rule{f.Include, f.Exclude} {
+		for i := range rules {
+			if err := rules[i].compile(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// compile checks the rule as for Filter.Validate.
+func (r *Rule) compile() error {
+	if r.Subject == "" && len(r.Authors) == 0 && len(r.Types) == 0 && !r.Merges && len(r.Paths) == 0 {
+		return errors.New("filter rule has no subject, authors, types, merges or paths, so would match every commit")
+	}
+	var err error
+	if r.Subject != "" {
+		if r.subject, err = regexp.Compile(r.Subject); err != nil {
+			return fmt.Errorf("invalid subject filter: %w", err)
+		}
+	}
+	r.authors = make([]*regexp.Regexp, len(r.Authors))
+	for i, pattern := range r.Authors {
+		r.authors[i] = wildcardPattern(pattern)
+	}
+	return nil
+}
+
+// NeedsFiles reports whether any rule matches on the files touched by a
+// commit, which are not included in the commits of a GitHub comparison and
+// have to be filled in for the rule to match.
+func (f Filter) NeedsFiles() bool {
+	return slices.ContainsFunc(slices.Concat(f.Include, f.Exclude), func(r Rule) bool {
+		return len(r.Paths) > 0
+	})
+}
+
+// Apply returns a copy of comparison with only the commits which f keeps,
+// along with how many commits were hidden for each reason, in order of the
+// first commit hidden for it.
+func (f Filter) Apply(comparison *github.CommitsComparison) (*github.CommitsComparison, []Hidden, error) {
+	if err := f.Validate(); err != nil {
+		return nil, nil, err
+	}
+	kept := *comparison
+	kept.Commits = nil
+
+	var hidden []Hidden
+	hide := func(reason string) {
+		if i := slices.IndexFunc(hidden, func(h Hidden) bool { return h.Reason == reason }); i != -1 {
+			hidden[i].Count++
+		} else {
+			hidden = append(hidden, Hidden{reason, 1})
+		}
+	}
+	for _, c := range comparison.Commits {
+		if len(f.Include) > 0 && !slices.ContainsFunc(f.Include, func(r Rule) bool { return r.matches(c) }) {
+			hide("not included")
+			continue
+		}
+		if i := slices.IndexFunc(f.Exclude, func(r Rule) bool { return r.matches(c) }); i != -1 {
+			hide(f.Exclude[i].reason())
+			continue
+		}
+		kept.Commits = append(kept.Commits, c)
+	}
+	return &kept, hidden, nil
+}
+
+func (r Rule) matches(c github.RepositoryCommit) bool {
+	if r.subject != nil && !r.subject.MatchString(firstCommitMsgLine(c)) {
+		return false
+	}
+	if len(r.authors) > 0 {
+		author := cmp.Or(c.GetAuthor().GetLogin(), c.GetCommit().GetAuthor().GetName())
+		if !slices.ContainsFunc(r.authors, func(re *regexp.Regexp) bool { return re.MatchString(author) }) {
+			return false
+		}
+	}
+	if len(r.Types) > 0 {
+		typ, _, ok := plan.ParseConventional(c.GetCommit().GetMessage())
+		if !ok || !slices.ContainsFunc(r.Types, func(t string) bool { return strings.EqualFold(t, typ) }) {
+			return false
+		}
+	}
+	if r.Merges && len(c.Parents) < 2 {
+		return false
+	}
+	if len(r.Paths) > 0 {
+		if len(c.Files) == 0 {
+			return false
+		}
+		for _, file := range c.Files {
+			if !slices.ContainsFunc(r.Paths, func(pattern string) bool { return pathMatch(pattern, file.GetFilename()) }) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// reason describes the commits matched by the rule.
+func (r Rule) reason() string {
+	if r.Reason != "" {
+		return r.Reason
+	}
+	var parts []string
+	if r.Merges {
+		parts = append(parts, "merge commits")
+	}
+	if len(r.Types) > 0 {
+		parts = append(parts, strings.Join(r.Types, "/")+" commits")
+	}
+	if len(r.Authors) > 0 {
+		parts = append(parts, "by "+strings.Join(r.Authors, ", "))
+	}
+	if r.Subject != "" {
+		parts = append(parts, fmt.Sprintf("matching %q", r.Subject))
+	}
+	if len(r.Paths) > 0 {
+		parts = append(parts, "only touching "+strings.Join(r.Paths, ", "))
+	}
+	return strings.Join(parts, " ")
+}
+
+// wildcardPattern compiles pattern to a regular expression matching whole
+// strings, ignoring case, where * in pattern matches any run of characters and
+// everything else is literal.
+func wildcardPattern(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return regexp.MustCompile(`(?i)^` + strings.Join(parts, ".*") + `$`)
+}
+
+// pathMatch reports whether file matches pattern, either a directory ending
+// in "/" which file is within, or a glob matched against the whole path, or
+// just the file name if the pattern has no "/".
+func pathMatch(pattern, file string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(file, pattern)
+	}
+	if !strings.Contains(pattern, "/") {
+		file = path.Base(file)
+	}
+	ok, _ := path.Match(pattern, file)
+	return ok
+}
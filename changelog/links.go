@@ -0,0 +1,124 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Links turns the references in commit subjects, and commit SHAs, into links
+// to the GitHub repository Owner/Repo or to external issue trackers.
+//
+// A nil *Links leaves everything as plain text.
+type Links struct {
+	Owner, Repo string
+	Trackers    []Tracker
+}
+
+// Tracker links references to an external issue tracker, such as "PROJ-88"
+// for a Jira project.
+type Tracker struct {
+	// Pattern is a regular expression matching a reference, e.g. `PROJ-\d+`.
+	Pattern string `json:"pattern"`
+	// URL is the template of the link for a reference, in which $0 is the
+	// whole reference and $1 etc. the submatches of Pattern, e.g.
+	// "https://example.atlassian.net/browse/$0".
+	URL string `json:"url"`
+}
+
+// Validate checks that the pattern of t compiles and it has a URL.
+func (t Tracker) Validate() error {
+	if _, err := regexp.Compile(t.Pattern); err != nil {
+		return fmt.Errorf("invalid tracker pattern: %w", err)
+	}
+	if t.URL == "" {
+		return fmt.Errorf("tracker %q has no url", t.Pattern)
+	}
+	return nil
+}
+
+// issuePattern matches GitHub issue and pull request references, "#123" or
+// "GH-123", capturing the number.
+var issuePattern = regexp.MustCompile(`(?:#|\bGH-)(\d+)\b`)
+
+// link is a reference at s[start:end] of some text, linking to url.
+type link struct {
+	start, end int
+	url        string
+}
+
+// find returns the references in s, in order. Where references overlap, the
+// first one found is kept, with the GitHub references found first.
+func (l *Links) find(s string) []link {
+	if l == nil {
+		return nil
+	}
+	var links []link
+	add := func(start, end int, url string) {
+		overlaps := slices.ContainsFunc(links, func(o link) bool { return start < o.end && o.start < end })
+		if !overlaps {
+			links = append(links, link{start, end, url})
+		}
+	}
+	for _, m := range issuePattern.FindAllStringSubmatchIndex(s, -1) {
+		// skip the likes of "abc#1" and HTML entities such as "&#39;"
+		if s[m[0]] == '#' && m[0] > 0 && (isWordByte(s[m[0]-1]) || s[m[0]-1] == '&') {
+			continue
+		}
+		add(m[0], m[1], fmt.Sprintf("https://github.com/%s/%s/issues/%s", l.Owner, l.Repo, s[m[2]:m[3]]))
+	}
+	for _, t := range l.Trackers {
+		re, err := regexp.Compile(t.Pattern)
+		if err != nil {
+			continue // ruled out by Validate
+		}
+		for _, m := range re.FindAllStringSubmatchIndex(s, -1) {
+			add(m[0], m[1], string(re.ExpandString(nil, t.URL, s, m)))
+		}
+	}
+	slices.SortFunc(links, func(a, b link) int { return a.start - b.start })
+	return links
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// linkify formats each of the links found in s with format.
+func linkify(s string, links []link, format func(text, url string) string) string {
+	var buf strings.Builder
+	last := 0
+	for _, ln := range links {
+		buf.WriteString(s[last:ln.start])
+		buf.WriteString(format(s[ln.start:ln.end], ln.url))
+		last = ln.end
+	}
+	buf.WriteString(s[last:])
+	return buf.String()
+}
+
+// markdown returns s with its references as markdown links.
+func (l *Links) markdown(s string) string {
+	return linkify(s, l.find(s), markdownLink)
+}
+
+// commit returns the short form of sha, formatted as a link to the commit
+// with format.
+func (l *Links) commit(sha string, format func(text, url string) string) string {
+	short := sha[:min(7, len(sha))]
+	if l == nil || sha == "" {
+		return short
+	}
+	return format(short, fmt.Sprintf("https://github.com/%s/%s/commit/%s", l.Owner, l.Repo, sha))
+}
+
+func markdownLink(text, url string) string {
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+// terminalLink formats an OSC 8 hyperlink, which most terminals make
+// clickable, and the rest show as plain text.
+func terminalLink(text, url string) string {
+	return fmt.Sprintf("\x1b]8;;%s\x1b\\%s\x1b]8;;\x1b\\", url, text)
+}
@@ -0,0 +1,72 @@
+package changelog
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mroth/bump/plan"
+)
+
+// ANSI escape codes for coloring the types of conventional commits.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiFaint   = "\x1b[2m"
+	ansiRed     = "\x1b[1;31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+)
+
+// typeColors are the colors of conventional commit types, with all other
+// types faint, and breaking changes of any type red.
+var typeColors = map[string]string{
+	"feat": ansiGreen,
+	"fix":  ansiYellow,
+	"perf": ansiMagenta,
+	"docs": ansiBlue,
+}
+
+// screenSubject formats the subject of a commit for the screen, truncated
+// with an ellipsis to width if it is positive, with its references as
+// terminal hyperlinks and its conventional commit type colored, as set in
+// opts.
+func screenSubject(subject string, width int, opts Options) string {
+	links := opts.Links.find(subject)
+
+	// the end of the type, scope and "!" before the colon, if any
+	var header int
+	typ, breaking, ok := plan.ParseConventional(subject)
+	if ok && opts.Color {
+		header = strings.Index(subject, ":")
+	}
+
+	if width > 0 && utf8.RuneCountInString(subject) > width {
+		cut := 0
+		for range width - 1 {
+			_, size := utf8.DecodeRuneInString(subject[cut:])
+			cut += size
+		}
+		subject = subject[:cut] + "…"
+		links = slices.DeleteFunc(links, func(l link) bool { return l.end > cut })
+		header = min(header, cut)
+	}
+
+	// leave the type uncolored in the unlikely event it is linked
+	if len(links) > 0 && links[0].start < header {
+		header = 0
+	}
+	s := linkify(subject, links, terminalLink)
+	if header == 0 {
+		return s
+	}
+	color := typeColors[typ]
+	switch {
+	case breaking:
+		color = ansiRed
+	case color == "":
+		color = ansiFaint
+	}
+	return color + s[:header] + ansiReset + s[header:]
+}
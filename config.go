@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/mroth/bump/changelog"
+	"github.com/mroth/bump/plan"
+	"github.com/mroth/bump/versionfile"
+)
+
+// ConfigFileName is the name of the optional per-repository configuration
+// file, looked for at the root of the local git repository.
+const ConfigFileName = ".bump.json"
+
+// Config defines settings which are specific to a repository, and thus live
+// alongside its source rather than in the environment or flags.
+//
+// The zero value represents the program defaults.
+type Config struct {
+	// Checks overrides the level of individual preflight checks, keyed by
+	// check name (e.g. "dirty": "block").
+	Checks map[string]CheckLevel `json:"checks,omitempty"`
+
+	// ChangelogFile is the path of a Keep a Changelog format file, relative
+	// to the repository root, to update for each release.
+	ChangelogFile string `json:"changelog_file,omitempty"`
+
+	// VersionFiles lists files which record the project version, to be
+	// updated to the new version for each release.
+	VersionFiles []versionfile.File `json:"version_files,omitempty"`
+
+	// Scheme is the versioning scheme of the repository: "semver" (the
+	// default), or a CalVer format such as "YYYY.MM.MICRO".
+	Scheme string `json:"scheme,omitempty"`
+
+	// TagMode is how strictly release tags are parsed as versions: "lenient"
+	// (the default) or "strict".
+	TagMode string `json:"tag_mode,omitempty"`
+
+	// Metadata is a template for build metadata to add to each version, as
+	// for the --metadata flag.
+	Metadata string `json:"metadata,omitempty"`
+
+	// Remotes lists the names of git remotes to look for the GitHub
+	// repository in, in order of preference. Defaults to upstream, then
+	// origin.
+	Remotes []string `json:"remotes,omitempty"`
+
+	// Filter hides noise, such as merge commits or dependency bumps by bots,
+	// from the changelog.
+	Filter changelog.Filter `json:"filter,omitzero"`
+
+	// Trackers link references to external issue trackers in the changelog,
+	// alongside the GitHub issue references which are always linked.
+	Trackers []changelog.Tracker `json:"trackers,omitempty"`
+
+	// Credits controls crediting contributors in the release notes.
+	Credits Credits `json:"credits,omitzero"`
+
+	// Hooks are commands to run at fixed points of each release.
+	Hooks Hooks `json:"hooks,omitzero"`
+}
+
+// Credits controls how contributors are credited in the release notes.
+type Credits struct {
+	Authors      bool `json:"authors,omitempty"`      // credit the authors of each commit
+	Contributors bool `json:"contributors,omitempty"` // thank everyone who contributed
+	FirstTime    bool `json:"first_time,omitempty"`   // welcome first-time contributors
+}
+
+// LoadConfig reads the ConfigFileName in dir. A missing file is not an error,
+// and results in the zero value Config.
+func LoadConfig(dir string) (*Config, error) {
+	var cfg Config
+	path := filepath.Join(dir, ConfigFileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// unknown keys are most likely typos, which would otherwise be silently
+	// ignored, e.g. "type" for "types" leaving a filter rule matching anything
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+func (c *Config) validate() error {
+	for name, level := range c.Checks {
+		if findPreflightCheck(name) == nil {
+			return fmt.Errorf("unknown check %q", name)
+		}
+		if !level.valid() {
+			return fmt.Errorf("check %q: unknown level %q (want off, warn or block)", name, level)
+		}
+	}
+	if _, err := plan.ParseScheme(c.Scheme); err != nil {
+		return err
+	}
+	if _, err := plan.ParseTagMode(c.TagMode); err != nil {
+		return err
+	}
+	if err := c.Filter.Validate(); err != nil {
+		return err
+	}
+	for _, t := range c.Trackers {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, f := range c.VersionFiles {
+		if _, err := f.Updater(); err != nil {
+			return fmt.Errorf("version file %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mroth/bump/changelog"
+	"github.com/mroth/bump/plan"
+	"github.com/mroth/bump/release"
+	"github.com/mroth/bump/versionfile"
+)
+
+// releasePlan is everything a run would do to draft a release, once the next
+// version has been chosen.
+type releasePlan struct {
+	Owner, Repo string
+	Target      string // empty for the default branch
+	Release     changelog.FileRelease
+	Body        string
+	Files       []fileChange
+	Hooks       Hooks
+	Opts        Options
+}
+
+// printPlan writes a description of the plan to w, in the order the steps
+// would happen.
+func printPlan(w io.Writer, p releasePlan, cfg *Config) {
+	tag := "v" + plan.VersionString(p.Release.Version)
+	fmt.Fprintln(w, "🧪 Dry run, nothing has been changed. bump would:")
+	fmt.Fprintln(w)
+
+	hooks := func(point string, cmds []string) {
+		for _, c := range cmds {
+			fmt.Fprintf(w, "  - run %s hook: %s\n", point, c)
+		}
+	}
+	hooks("after_version", p.Hooks.AfterVersion)
+
+	changed := make([]string, len(p.Files))
+	for i, f := range p.Files {
+		changed[i] = f.Path
+		if f.isChangelog(cfg, p.Opts) {
+			fmt.Fprintf(w, "  - add a %s section to %s\n", plan.VersionString(p.Release.Version), f.Path)
+			continue
+		}
+		fmt.Fprintf(w, "  - update %s:\n", f.Path)
+		for _, line := range strings.Split(strings.TrimSuffix(versionfile.Diff(f.Path, f.Before, f.After), "\n"), "\n") {
+			fmt.Fprintf(w, "      %s\n", line)
+		}
+	}
+	if len(changed) > 0 && p.Opts.Commit {
+		fmt.Fprintf(w, "  - commit %s as %q\n", strings.Join(changed, ", "), releaseCommitMessage(p.Release.Version))
+	}
+
+	hooks("before_draft", p.Hooks.BeforeDraft)
+	draftURL := release.DraftURL(p.Owner, p.Repo, p.Release.Version, p.Target, p.Body)
+	switch {
+	case p.Opts.API:
+		fmt.Fprintf(w, "  - call the GitHub API: POST /repos/%s/%s/releases (draft %s from %s)\n",
+			p.Owner, p.Repo, tag, cmp.Or(p.Target, "default branch"))
+		if !p.Opts.NoOpen {
+			fmt.Fprintln(w, "  - open the created draft release in your browser")
+		}
+	case p.Opts.Offline, p.Opts.NoOpen:
+		fmt.Fprintf(w, "  - print the URL: %s\n", draftURL)
+	default:
+		fmt.Fprintf(w, "  - open the URL: %s\n", draftURL)
+	}
+	hooks("after_draft", p.Hooks.AfterDraft)
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Release notes for %s:\n\n", boldStyler(tag))
+	fmt.Fprintln(w, p.Body)
+}
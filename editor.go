@@ -0,0 +1,60 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// errEmptyNotes is returned by editNotes when the user emptied the release
+// notes, which like an empty git commit message aborts the release.
+var errEmptyNotes = errors.New("aborting release due to empty release notes")
+
+// editNotes lets the user edit the release notes body in their preferred
+// editor, returning the edited text.
+func editNotes(ctx context.Context, env *environment, body string) (string, error) {
+	f, err := os.CreateTemp("", "bump-release-notes-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.WriteString(f, body); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	cmd := editorCommand(ctx, f.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = env.Stdin, env.Stdout, env.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(string(edited)) == "" {
+		return "", errEmptyNotes
+	}
+	return string(edited), nil
+}
+
+// editorCommand opens path in $VISUAL or $EDITOR, falling back to vi (or
+// notepad on Windows) as git does. The editor variable may include arguments,
+// e.g. "code --wait".
+func editorCommand(ctx context.Context, path string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		editor := cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"), "notepad")
+		return exec.CommandContext(ctx, "cmd", "/C", editor, path)
+	}
+	editor := cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"), "vi")
+	return exec.CommandContext(ctx, "sh", "-c", editor+` "$@"`, editor, path)
+}
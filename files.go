@@ -0,0 +1,160 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5"
+	"github.com/mroth/bump/changelog"
+	"github.com/mroth/bump/plan"
+	"github.com/mroth/bump/versionfile"
+)
+
+// fileChange is a planned change to the contents of a file in the local
+// repository, at Path relative to its root.
+type fileChange struct {
+	Path          string
+	Before, After []byte
+}
+
+// planReleaseFiles works out the changes to the version files and changelog
+// file configured for the repository, without writing anything. Nothing is
+// planned unless every file can be updated.
+func planReleaseFiles(root string, cfg *Config, opts Options, rel changelog.FileRelease) ([]fileChange, error) {
+	var changes []fileChange
+	for _, f := range cfg.VersionFiles {
+		u, err := f.Updater()
+		if err != nil {
+			return nil, err
+		}
+		before, err := os.ReadFile(filepath.Join(root, f.Path))
+		if err != nil {
+			return nil, err
+		}
+		after, err := u.Update(before, rel.Previous, rel.Version)
+		if err != nil {
+			return nil, fmt.Errorf("updating %s: %w", f.Path, err)
+		}
+		changes = append(changes, fileChange{f.Path, before, after})
+	}
+
+	if changelogFile := cmp.Or(opts.ChangelogFile, cfg.ChangelogFile); changelogFile != "" {
+		before, err := os.ReadFile(filepath.Join(root, changelogFile))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to update %s: %w", changelogFile, err)
+		}
+		after := []byte(changelog.UpdateFile(string(before), rel))
+		changes = append(changes, fileChange{changelogFile, before, after})
+	}
+	return changes, nil
+}
+
+// isChangelog reports whether the change is to the changelog file, for which
+// a diff would be too long to be useful on screen.
+func (c fileChange) isChangelog(cfg *Config, opts Options) bool {
+	return c.Path == cmp.Or(opts.ChangelogFile, cfg.ChangelogFile)
+}
+
+// updateReleaseFiles updates the version files and changelog file configured
+// for the repository with its working tree at root, showing diffs of the version files, then either commits
+// them or leaves them for review.
+func updateReleaseFiles(env *environment, root string, gitRepo *git.Repository, cfg *Config, opts Options, rel changelog.FileRelease) error {
+	changes, err := planReleaseFiles(root, cfg, opts, rel)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	if opts.Commit {
+		paths := make([]string, len(changes))
+		for i, c := range changes {
+			paths[i] = c.Path
+		}
+		staged, err := stagedExcept(gitRepo, paths)
+		if err != nil {
+			return err
+		}
+		if len(staged) > 0 {
+			return fmt.Errorf("--commit would also commit the staged %s, unstage or commit them first",
+				strings.Join(staged, ", "))
+		}
+	}
+
+	changed := make([]string, len(changes))
+	for i, c := range changes {
+		if !c.isChangelog(cfg, opts) {
+			fmt.Fprint(env.Stdout, versionfile.Diff(c.Path, c.Before, c.After))
+		}
+		path := filepath.Join(root, c.Path)
+		mode := os.FileMode(0644) // for a new changelog
+		if fi, err := os.Stat(path); err == nil {
+			mode = fi.Mode().Perm()
+		}
+		if err := os.WriteFile(path, c.After, mode); err != nil {
+			return err
+		}
+		changed[i] = c.Path
+	}
+
+	files := strings.Join(changed, ", ")
+	if !opts.Commit {
+		fmt.Fprintf(env.Stdout, "📝 Updated %s, review and commit before publishing the release\n", files)
+		return nil
+	}
+	msg := releaseCommitMessage(rel.Version)
+	if err := commitFiles(gitRepo, changed, msg); err != nil {
+		return fmt.Errorf("failed to commit %s: %w", files, err)
+	}
+	fmt.Fprintf(env.Stdout, "📝 Committed %s as %q, push it before publishing the release\n", files, msg)
+	return nil
+}
+
+func releaseCommitMessage(v *semver.Version) string {
+	return "Release v" + plan.VersionString(v)
+}
+
+// stagedExcept returns the files staged in the index of r other than those at
+// paths, which a commit of paths would sweep up along with them.
+func stagedExcept(r *git.Repository, paths []string) ([]string, error) {
+	wt, err := r.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+	var staged []string
+	for path, s := range status {
+		if s.Staging == git.Unmodified || s.Staging == git.Untracked {
+			continue
+		}
+		if !slices.ContainsFunc(paths, func(p string) bool { return filepath.ToSlash(p) == path }) {
+			staged = append(staged, path)
+		}
+	}
+	slices.Sort(staged)
+	return staged, nil
+}
+
+// commitFiles commits the files at paths, relative to the root of the local
+// repository, using the author from the user's git config.
+func commitFiles(r *git.Repository, paths []string, msg string) error {
+	wt, err := r.Worktree()
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if _, err := wt.Add(filepath.ToSlash(p)); err != nil {
+			return err
+		}
+	}
+	_, err = wt.Commit(msg, &git.CommitOptions{})
+	return err
+}
@@ -11,6 +11,7 @@ require (
 	github.com/manifoldco/promptui v0.9.0
 	github.com/pkg/browser v0.0.0-20210911075715-681adbf594b8
 	golang.org/x/oauth2 v0.36.0
+	golang.org/x/term v0.42.0
 )
 
 require (
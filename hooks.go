@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/mroth/bump/plan"
+)
+
+// Hooks are shell commands run at fixed points of a release, such as running
+// tests before drafting or announcing the release afterwards.
+type Hooks struct {
+	AfterVersion []string `json:"after_version,omitempty"` // next version was chosen
+	BeforeDraft  []string `json:"before_draft,omitempty"`  // browser opened or API called next
+	AfterDraft   []string `json:"after_draft,omitempty"`   // release was drafted
+}
+
+// hookRelease describes the release to hooks, via environment variables.
+type hookRelease struct {
+	Owner, Repo    string
+	Previous, Next *semver.Version
+	DraftURL       string // empty until known
+}
+
+func (h hookRelease) environ() []string {
+	return []string{
+		"BUMP_OWNER=" + h.Owner,
+		"BUMP_REPO=" + h.Repo,
+		"BUMP_PREVIOUS_VERSION=" + plan.VersionString(h.Previous),
+		"BUMP_NEXT_VERSION=" + plan.VersionString(h.Next),
+		"BUMP_TAG=v" + plan.VersionString(h.Next),
+		"BUMP_DRAFT_URL=" + h.DraftURL,
+	}
+}
+
+// runHooks runs the hook commands for point in order from dir, stopping at
+// the first which fails. The output of a failed hook is written to w, while
+// output of successful hooks is only logged in verbose mode.
+func runHooks(ctx context.Context, w io.Writer, dir, point string, cmds []string, rel hookRelease) error {
+	for _, c := range cmds {
+		fmt.Fprintf(w, "🪝 %s %s\n", c, faintStyler("("+point+")"))
+		start := time.Now()
+		cmd := shellCommand(ctx, c)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), rel.environ()...)
+		out, err := cmd.CombinedOutput()
+		timeTrack(start, point+" hook")
+		if err != nil {
+			w.Write(out)
+			return fmt.Errorf("%s hook %q failed: %w", point, c, err)
+		}
+		logVerbose("%s hook output:\n%s", point, out)
+	}
+	return nil
+}
+
+// shellCommand runs command line s via the platform shell.
+func shellCommand(ctx context.Context, s string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", s)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", s)
+}
@@ -0,0 +1,4 @@
+// Package bumptest provides fixtures and fakes shared by the tests of the
+// other bump packages: sample commit data, throwaway git repositories, and an
+// httptest backed fake of the GitHub API.
+package bumptest
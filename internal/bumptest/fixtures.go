@@ -1,51 +1,48 @@
-package main
+package bumptest
 
 import (
-	"flag"
-	"os"
-	"path/filepath"
-	"testing"
 	"time"
 
-	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-github/v29/github"
 )
 
-var update = flag.Bool("update", false, "update golden files")
-
-// currentDate represents the current time for test data generation
-var currentDate = time.Date(2025, 7, 22, 20, 56, 6, 0, time.UTC)
+// Now represents the current time for test data generation.
+var Now = time.Date(2025, 7, 22, 20, 56, 6, 0, time.UTC)
 
 // timePtr returns a pointer to the given time - helper for creating *time.Time values
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
 
-// testCommitsComparisons contains sample data for testing changelog functions
+// CommitsComparisons contains sample data for testing changelog functions,
+// in the reverse chronological order the rest of the program uses.
+//
 // NOTE: this test data is AI generated and for testing purposes only.
-var testCommitsComparisons = map[string]*github.CommitsComparison{
+var CommitsComparisons = map[string]*github.CommitsComparison{
 	"sample": {
 		HTMLURL: github.String("https://github.com/owner/repo/compare/v1.0.0...v1.1.0"),
 		Commits: []github.RepositoryCommit{
 			{
-				SHA: github.String("a1b2c3d4e5f6789012345678901234567890abcd"),
+				SHA:    github.String("a1b2c3d4e5f6789012345678901234567890abcd"),
+				Author: &github.User{Login: github.String("alicej")},
 				Commit: &github.Commit{
 					Message: github.String("feat: add new user authentication system\n\nImplemented OAuth2 integration with Google and GitHub providers.\nAdded user session management and JWT token handling."),
 					Author: &github.CommitAuthor{
 						Name:  github.String("Alice Johnson"),
 						Email: github.String("alice@example.com"),
-						Date:  timePtr(currentDate.Add(-5 * time.Minute)),
+						Date:  timePtr(Now.Add(-5 * time.Minute)),
 					},
 				},
 			},
 			{
-				SHA: github.String("b2c3d4e5f6789012345678901234567890abcdef"),
+				SHA:    github.String("b2c3d4e5f6789012345678901234567890abcdef"),
+				Author: &github.User{Login: github.String("bobsmith")},
 				Commit: &github.Commit{
-					Message: github.String("fix: resolve memory leak in background worker\n\nFixed goroutine leak that was causing memory usage to grow over time.\nImproved error handling and added proper cleanup."),
+					Message: github.String("fix: resolve memory leak in background worker\n\nFixed goroutine leak that was causing memory usage to grow over time.\nImproved error handling and added proper cleanup.\n\nCo-authored-by: Dana Lee <12345+danalee@users.noreply.github.com>\nCo-authored-by: Alice Johnson <alice@example.com>"),
 					Author: &github.CommitAuthor{
 						Name:  github.String("Bob Smith"),
 						Email: github.String("bob@example.com"),
-						Date:  timePtr(currentDate.Add(-2 * time.Hour)),
+						Date:  timePtr(Now.Add(-2 * time.Hour)),
 					},
 				},
 			},
@@ -56,7 +53,7 @@ var testCommitsComparisons = map[string]*github.CommitsComparison{
 					Author: &github.CommitAuthor{
 						Name:  github.String("Carol Williams"),
 						Email: github.String("carol@example.com"),
-						Date:  timePtr(currentDate.Add(-6 * time.Hour)),
+						Date:  timePtr(Now.Add(-6 * time.Hour)),
 					},
 				},
 			},
@@ -67,7 +64,7 @@ var testCommitsComparisons = map[string]*github.CommitsComparison{
 					Author: &github.CommitAuthor{
 						Name:  github.String("Alice Johnson"),
 						Email: github.String("alice@example.com"),
-						Date:  timePtr(currentDate.Add(-1 * 24 * time.Hour)),
+						Date:  timePtr(Now.Add(-1 * 24 * time.Hour)),
 					},
 				},
 			},
@@ -78,7 +75,7 @@ var testCommitsComparisons = map[string]*github.CommitsComparison{
 					Author: &github.CommitAuthor{
 						Name:  github.String("David Brown"),
 						Email: github.String("david@example.com"),
-						Date:  timePtr(currentDate.Add(-3 * 24 * time.Hour)),
+						Date:  timePtr(Now.Add(-3 * 24 * time.Hour)),
 					},
 				},
 			},
@@ -89,7 +86,7 @@ var testCommitsComparisons = map[string]*github.CommitsComparison{
 					Author: &github.CommitAuthor{
 						Name:  github.String("Eva Davis"),
 						Email: github.String("eva@example.com"),
-						Date:  timePtr(currentDate.Add(-5 * 24 * time.Hour)),
+						Date:  timePtr(Now.Add(-5 * 24 * time.Hour)),
 					},
 				},
 			},
@@ -100,7 +97,7 @@ var testCommitsComparisons = map[string]*github.CommitsComparison{
 					Author: &github.CommitAuthor{
 						Name:  github.String("Bob Smith"),
 						Email: github.String("bob@example.com"),
-						Date:  timePtr(currentDate.Add(-7 * 24 * time.Hour)),
+						Date:  timePtr(Now.Add(-7 * 24 * time.Hour)),
 					},
 				},
 			},
@@ -111,7 +108,7 @@ var testCommitsComparisons = map[string]*github.CommitsComparison{
 					Author: &github.CommitAuthor{
 						Name:  github.String("Alice Johnson"),
 						Email: github.String("alice@example.com"),
-						Date:  timePtr(currentDate.Add(-2 * 7 * 24 * time.Hour)),
+						Date:  timePtr(Now.Add(-2 * 7 * 24 * time.Hour)),
 					},
 				},
 			},
@@ -122,7 +119,7 @@ var testCommitsComparisons = map[string]*github.CommitsComparison{
 					Author: &github.CommitAuthor{
 						Name:  github.String("Frank Miller"),
 						Email: github.String("frank@example.com"),
-						Date:  timePtr(currentDate.Add(-3 * 7 * 24 * time.Hour)),
+						Date:  timePtr(Now.Add(-3 * 7 * 24 * time.Hour)),
 					},
 				},
 			},
@@ -133,7 +130,7 @@ var testCommitsComparisons = map[string]*github.CommitsComparison{
 					Author: &github.CommitAuthor{
 						Name:  github.String("Grace Wilson"),
 						Email: github.String("grace@example.com"),
-						Date:  timePtr(currentDate.Add(-6 * 7 * 24 * time.Hour)),
+						Date:  timePtr(Now.Add(-6 * 7 * 24 * time.Hour)),
 					},
 				},
 			},
@@ -144,7 +141,7 @@ var testCommitsComparisons = map[string]*github.CommitsComparison{
 					Author: &github.CommitAuthor{
 						Name:  github.String("David Brown"),
 						Email: github.String("david@example.com"),
-						Date:  timePtr(currentDate.Add(-2 * 30 * 24 * time.Hour)),
+						Date:  timePtr(Now.Add(-2 * 30 * 24 * time.Hour)),
 					},
 				},
 			},
@@ -155,66 +152,10 @@ var testCommitsComparisons = map[string]*github.CommitsComparison{
 					Author: &github.CommitAuthor{
 						Name:  github.String("Eva Davis"),
 						Email: github.String("eva@example.com"),
-						Date:  timePtr(currentDate.Add(-3 * 30 * 24 * time.Hour)),
+						Date:  timePtr(Now.Add(-3 * 30 * 24 * time.Hour)),
 					},
 				},
 			},
 		},
 	},
 }
-
-func TestRenderChangelogScreen(t *testing.T) {
-	for name, comparison := range testCommitsComparisons {
-		t.Run(name, func(t *testing.T) {
-			got := RenderChangelogScreen(comparison)
-
-			goldenFile := filepath.Join("testdata", name+"_screen.golden")
-
-			if *update {
-				err := os.WriteFile(goldenFile, []byte(got), 0644)
-				if err != nil {
-					t.Fatalf("failed to update golden file: %v", err)
-				}
-				return
-			}
-
-			wantBytes, err := os.ReadFile(goldenFile)
-			if err != nil {
-				t.Fatalf("failed to read golden file %s: %v", goldenFile, err)
-			}
-			want := string(wantBytes)
-
-			if diff := cmp.Diff(want, got); diff != "" {
-				t.Errorf("RenderChangelogScreen() mismatch (-want +got):\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestRenderChangelogMarkdown(t *testing.T) {
-	for name, comparison := range testCommitsComparisons {
-		t.Run(name, func(t *testing.T) {
-			got := RenderChangelogMarkdown(comparison)
-
-			goldenFile := filepath.Join("testdata", name+"_markdown.golden")
-
-			if *update {
-				err := os.WriteFile(goldenFile, []byte(got), 0644)
-				if err != nil {
-					t.Fatalf("failed to update golden file: %v", err)
-				}
-				return
-			}
-
-			wantBytes, err := os.ReadFile(goldenFile)
-			if err != nil {
-				t.Fatalf("failed to read golden file %s: %v", goldenFile, err)
-			}
-			want := string(wantBytes)
-
-			if diff := cmp.Diff(want, got); diff != "" {
-				t.Errorf("RenderChangelogMarkdown() mismatch (-want +got):\n%s", diff)
-			}
-		})
-	}
-}
@@ -0,0 +1,140 @@
+package bumptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v29/github"
+)
+
+// GitHub is an httptest backed stand in for the GitHub API, serving canned
+// responses for a single repository, and the repositories of organizations.
+type GitHub struct {
+	*httptest.Server
+	DefaultBranch string
+	Refs          map[string]string // ref name to commit SHA
+	Releases      []*github.RepositoryRelease
+	Tags          []string
+	Contributors  []string            // logins or emails of authors with earlier commits
+	CommitFiles   map[string][]string // files changed by each commit, by SHA
+
+	// Comparisons is keyed by "base...head", in the reverse chronological
+	// order the rest of the program uses (the fake serves them reversed, as
+	// the real API would).
+	Comparisons map[string]*github.CommitsComparison
+
+	Created []*github.RepositoryRelease // releases created via the API
+
+	Orgs map[string][]*github.Repository // repositories by organization
+}
+
+// NewGitHub starts a fake GitHub API server, which is closed when the test
+// finishes.
+func NewGitHub(t testing.TB) *GitHub {
+	t.Helper()
+	f := &GitHub{
+		DefaultBranch: "master",
+		Refs:          make(map[string]string),
+		Comparisons:   make(map[string]*github.CommitsComparison),
+		CommitFiles:   make(map[string][]string),
+		Orgs:          make(map[string][]*github.Repository),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/{owner}/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, &github.Repository{DefaultBranch: github.String(f.DefaultBranch)})
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/commits/{ref...}", func(w http.ResponseWriter, r *http.Request) {
+		if files, ok := f.CommitFiles[r.PathValue("ref")]; ok && !strings.Contains(r.Header.Get("Accept"), "sha") {
+			c := &github.RepositoryCommit{SHA: github.String(r.PathValue("ref"))}
+			for _, name := range files {
+				c.Files = append(c.Files, github.CommitFile{Filename: github.String(name)})
+			}
+			writeJSON(w, c)
+			return
+		}
+		sha, ok := f.Refs[r.PathValue("ref")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, sha) // requested with the sha media type
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/commits", func(w http.ResponseWriter, r *http.Request) {
+		commits := []*github.RepositoryCommit{}
+		if slices.Contains(f.Contributors, r.URL.Query().Get("author")) {
+			commits = append(commits, &github.RepositoryCommit{SHA: github.String("0000000")})
+		}
+		writeJSON(w, commits)
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		for _, rel := range f.Releases {
+			if !rel.GetDraft() && !rel.GetPrerelease() {
+				writeJSON(w, rel)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/releases", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, f.Releases)
+	})
+	mux.HandleFunc("POST /repos/{owner}/{repo}/releases", func(w http.ResponseWriter, r *http.Request) {
+		var rel github.RepositoryRelease
+		if err := json.NewDecoder(r.Body).Decode(&rel); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.Created = append(f.Created, &rel)
+		rel.HTMLURL = github.String(fmt.Sprintf("https://github.com/%s/%s/releases/tag/untagged-%d",
+			r.PathValue("owner"), r.PathValue("repo"), len(f.Created)))
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, &rel)
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/tags", func(w http.ResponseWriter, r *http.Request) {
+		tags := make([]*github.RepositoryTag, len(f.Tags))
+		for i, name := range f.Tags {
+			tags[i] = &github.RepositoryTag{Name: github.String(name)}
+		}
+		writeJSON(w, tags)
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/compare/{basehead...}", func(w http.ResponseWriter, r *http.Request) {
+		cc, ok := f.Comparisons[r.PathValue("basehead")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		api := *cc
+		api.Commits = slices.Clone(cc.Commits)
+		slices.Reverse(api.Commits)
+		writeJSON(w, &api)
+	})
+	mux.HandleFunc("GET /orgs/{org}/repos", func(w http.ResponseWriter, r *http.Request) {
+		repos, ok := f.Orgs[r.PathValue("org")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, repos)
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// APIClient returns a GitHub API client talking to the fake.
+func (f *GitHub) APIClient() *github.Client {
+	client := github.NewClient(f.Client())
+	client.BaseURL, _ = url.Parse(f.URL + "/")
+	return client
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
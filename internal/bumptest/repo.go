@@ -0,0 +1,167 @@
+package bumptest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Repo is a throwaway on-disk git repository for exercising code which
+// inspects a local working copy. Its methods fail the test on any error.
+type Repo struct {
+	*git.Repository
+	Dir string
+
+	t    testing.TB
+	when time.Time // author date of the next commit
+}
+
+// NewRepo initializes an empty repository in a temporary directory.
+func NewRepo(t testing.TB) *Repo {
+	t.Helper()
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Repo{Repository: r, Dir: dir, t: t, when: Now}
+}
+
+// AddRemote adds a remote with the given URL.
+func (tr *Repo) AddRemote(name, url string) {
+	tr.t.Helper()
+	_, err := tr.CreateRemote(&config.RemoteConfig{Name: name, URLs: []string{url}})
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+}
+
+// Commit writes content to file and commits it, returning the new commit hash.
+func (tr *Repo) Commit(file, content, msg string) plumbing.Hash {
+	tr.t.Helper()
+	tr.Write(file, content)
+	wt, err := tr.Worktree()
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+	if _, err := wt.Add(file); err != nil {
+		tr.t.Fatal(err)
+	}
+	h, err := wt.Commit(msg, &git.CommitOptions{Author: &object.Signature{
+		Name: "Test", Email: "test@example.com", When: tr.when,
+	}})
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+	tr.when = tr.when.Add(time.Minute)
+	return h
+}
+
+// Write writes content to file in the working tree.
+func (tr *Repo) Write(file, content string) {
+	tr.t.Helper()
+	err := os.WriteFile(filepath.Join(tr.Dir, file), []byte(content), 0644)
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+}
+
+// SetRef points ref at hash, e.g. to simulate the state of a pushed remote.
+func (tr *Repo) SetRef(ref plumbing.ReferenceName, hash plumbing.Hash) {
+	tr.t.Helper()
+	err := tr.Storer.SetReference(plumbing.NewHashReference(ref, hash))
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+}
+
+// Reset hard resets the current branch and working tree to hash.
+func (tr *Repo) Reset(hash plumbing.Hash) {
+	tr.t.Helper()
+	wt, err := tr.Worktree()
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+	if err := wt.Reset(&git.ResetOptions{Commit: hash, Mode: git.HardReset}); err != nil {
+		tr.t.Fatal(err)
+	}
+}
+
+// Checkout switches to branch, creating it at HEAD if create is set.
+func (tr *Repo) Checkout(branch string, create bool) {
+	tr.t.Helper()
+	wt, err := tr.Worktree()
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+	err = wt.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: create,
+	})
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+}
+
+// Tag creates a tag name pointing at hash, annotated or lightweight.
+func (tr *Repo) Tag(name string, hash plumbing.Hash, annotated bool) {
+	tr.t.Helper()
+	var opts *git.CreateTagOptions
+	if annotated {
+		opts = &git.CreateTagOptions{
+			Message: name,
+			Tagger:  &object.Signature{Name: "Test", Email: "test@example.com", When: tr.when},
+		}
+	}
+	if _, err := tr.CreateTag(name, hash, opts); err != nil {
+		tr.t.Fatal(err)
+	}
+}
+
+// AddWorktree adds a linked worktree of the repository in a temporary
+// directory, like git worktree add, with a new branch checked out at HEAD. It
+// returns the directory of the worktree.
+func (tr *Repo) AddWorktree(branch string) string {
+	tr.t.Helper()
+	head, err := tr.Head()
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+	tr.SetRef(plumbing.NewBranchReferenceName(branch), head.Hash())
+
+	dir := tr.t.TempDir()
+	gitDir := filepath.Join(tr.Dir, ".git", "worktrees", branch)
+	files := map[string]string{
+		filepath.Join(gitDir, "HEAD"):      "ref: " + plumbing.NewBranchReferenceName(branch).String() + "\n",
+		filepath.Join(gitDir, "commondir"): "../..\n",
+		filepath.Join(gitDir, "gitdir"):    filepath.Join(dir, ".git") + "\n",
+		filepath.Join(dir, ".git"):         "gitdir: " + gitDir + "\n",
+	}
+	if err := os.MkdirAll(gitDir, 0755); err != nil {
+		tr.t.Fatal(err)
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			tr.t.Fatal(err)
+		}
+	}
+
+	r, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+	if err := wt.Reset(&git.ResetOptions{Commit: head.Hash(), Mode: git.HardReset}); err != nil {
+		tr.t.Fatal(err)
+	}
+	return dir
+}
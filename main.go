@@ -1,14 +1,24 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5"
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/changelog"
+	"github.com/mroth/bump/plan"
+	"github.com/mroth/bump/release"
+	"github.com/mroth/bump/remote"
 	"github.com/pkg/browser"
 )
 
@@ -27,94 +37,479 @@ func timeTrack(start time.Time, name string) {
 }
 
 func main() {
-	owner, repo, opts := ParseAll()
+	cmd, owner, repo, opts := ParseAll()
 	VerboseLogging = opts.Verbose
+	colorOutput = colorEnabled(os.Stdout)
 	logVerbose("BUILD INFO: %v %v %v", buildVersion, buildCommit, buildDate)
-	logVerbose("ParseAll() opts: %+v owner: %v repo: %v", opts, owner, repo)
-
-	// figure out owner and repo
-	//  ...if we got it passed to us already, cool cool
-	//  ...if not, call githubRepoDetect() to do our git checking magic
-	if owner == "" || repo == "" {
-		logVerbose("owner/repo not specified, checking for local git repo")
-		wd, err := os.Getwd()
-		if err != nil {
-			// couldn't get working directory, something really weird going on
-			// we should just fatal in this case
-			log.Fatal(err)
+	logVerbose("ParseAll() cmd: %q opts: %+v owner: %v repo: %v", cmd, opts, owner, repo)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		// couldn't get working directory, something really weird going on
+		// we should just fatal in this case
+		log.Fatal(err)
+	}
+	env := &environment{
+		Dir:    wd,
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		GitHub: func(owner, repo string) release.Source { return release.NewGitHubSource(nil, owner, repo) },
+		OrgRepos: func(ctx context.Context, org string) ([]string, error) {
+			return release.OrgRepos(ctx, nil, org)
+		},
+		OpenURL: browser.OpenURL,
+		Now:     time.Now,
+	}
+
+	ctx := context.Background()
+	switch cmd {
+	case "next":
+		err = runNext(ctx, owner, repo, opts, env)
+	case "status":
+		err = runStatus(ctx, owner, repo, opts, env)
+	default:
+		err = run(ctx, owner, repo, opts, env)
+	}
+	if errors.Is(err, errUsage) {
+		if err != errUsage {
+			fmt.Fprintf(os.Stderr, "%v\n\n", err)
 		}
-		owner, repo, err = githubRepoDetect(wd)
-		// TODO: possibly warn if have commits ahead of remote locally?
-		if err != nil {
-			// probably just not in a git repo, no biggie
-			// just log what happened in verbose mode, and show usage
-			logVerbose("%v", err)
-			usage()
+		usage()
+	}
+	if errors.Is(err, errUnreleased) {
+		os.Exit(exitUnreleased)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// environment is everything in the outside world that run interacts with,
+// abstracted so the full program flow can be exercised in tests.
+type environment struct {
+	Dir      string        // working directory, for detecting local repo
+	Stdin    io.ReadCloser // input for interactive prompts
+	Stdout   io.Writer     // program output
+	Stderr   io.Writer     // interactive prompt UI
+	GitHub   func(owner, repo string) release.Source
+	OrgRepos func(ctx context.Context, org string) ([]string, error)
+	OpenURL  func(url string) error
+	Now      func() time.Time
+}
+
+// errUsage is returned by run when it could not figure out what to do, and
+// the user should be shown the usage instructions.
+var errUsage = errors.New("usage")
+
+// usageError is an errUsage explaining what was wrong, shown above the usage
+// instructions.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+func (e usageError) Is(target error) bool { return target == errUsage }
+
+// run drafts the next release of owner/repo.
+func run(ctx context.Context, owner, repo string, opts Options, env *environment) error {
+	if opts.API && opts.Offline {
+		return errors.New("--api and --offline cannot be used together")
+	}
+
+	// offline mode always needs the local clone, as that's where all the
+	// release information comes from, as does updating a changelog file.
+	ws, err := openWorkspace(owner, repo, opts.Offline || opts.ChangelogFile != "", true, opts, env)
+	if err != nil {
+		return err
+	}
+	owner, repo, target := ws.Owner, ws.Repo, ws.Target
+	gitRepo, root, cfg := ws.Git, ws.Root, ws.Config
+	source := ws.source(opts, env)
+
+	// since we are releasing from a local working copy, make sure it matches
+	// what GitHub will actually use to generate the release.
+	if gitRepo != nil {
+		if opts.SkipChecks {
+			logVerbose("skipping preflight checks")
+		} else {
+			rs, err := source.RemoteState(ctx, target)
+			if err != nil {
+				return err
+			}
+			rs.RemoteName = ws.Remote
+			if err := preflight(env.Stdout, gitRepo, rs, cfg); err != nil {
+				return err
+			}
 		}
-		logVerbose("detected .git repo with github remote %v/%v", owner, repo)
 	}
 
-	// get latest release version from github
-	logVerbose("checking github for latest release of %v/%v", owner, repo)
-	previousRelease, err := getLatestRelease(owner, repo)
+	// get latest release version. when releasing from a maintenance branch,
+	// that is the latest release in its line rather than the overall latest
+	// release of the repo.
+	mode, err := plan.ParseTagMode(cfg.TagMode)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	logVerbose("checking for previous release to %q", target)
+	previousRelease, err := release.Previous(ctx, source, target, mode)
+	if err != nil {
+		return err
 	}
 
-	// try to parse tag name from current release into a semantic version
-	previousVersion, err := semver.NewVersion(previousRelease.GetTagName())
+	// try to parse tag name from current release into a semantic version,
+	// falling back to an earlier release if it can't be.
+	previousVersion, err := mode.Parse(previousRelease.GetTagName())
 	if err != nil {
-		log.Fatal(err)
+		previousRelease, previousVersion, err = chooseBase(ctx, env, source, previousRelease.GetTagName(), mode)
+		if err != nil {
+			return err
+		}
 	}
-	fmt.Printf("🌻 Latest release of %v (published %v)\n",
-		boldStyler(fmt.Sprintf("%v/%v: %v", owner, repo, previousVersion)),
+	fmt.Fprintf(env.Stdout, "🌻 Latest release of %v (published %v)\n",
+		boldStyler(fmt.Sprintf("%v/%v: %v", owner, repo, plan.VersionString(previousVersion))),
 		previousRelease.GetPublishedAt().Format("2006 Jan 2"),
 	)
 
-	// retrieve changes since last release via GitHub API
-	comparison, err := compareRelease(owner, repo, previousRelease.GetTagName())
+	// retrieve changes since last release
+	comparison, err := source.Compare(ctx, previousRelease.GetTagName(), target)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve commits: %w", err)
+	}
+
+	// leave out the commits nobody wants to read about, keeping the full
+	// comparison for the build metadata, which is about the code released.
+	unfiltered := comparison
+	comparison, hidden, err := filterCommits(ctx, source, cfg.Filter, comparison)
 	if err != nil {
-		log.Fatal("failed to retrieve commits", err)
+		return err
 	}
 
 	// display abbreviated changelog to user in CLI, to hopefully aide them in
 	// making a decision about what the next semver should be.
-	changelog := RenderChangelogScreen(comparison)
-	fmt.Println(changelog)
+	links := &changelog.Links{Owner: owner, Repo: repo, Trackers: cfg.Trackers}
+	screen := screenOptions(env.Stdout, links)
+	screen.Hidden = hidden
+	fmt.Fprintln(env.Stdout, changelog.RenderScreenWith(comparison, screen))
+
+	// let the user look through every commit if asked to, as ten may not be
+	// enough to go on, leaving any out of the release notes as they see fit.
+	if opts.Review && len(comparison.Commits) > 0 {
+		comparison, err = review(ctx, source, comparison, screen.Height, env.Stdin, env.Stderr)
+		if err != nil {
+			return err
+		}
+	}
 
 	// invoke interactive prompt UI allowing user to select next version
-	nextVersion, err := prompt(previousVersion)
+	scheme, err := plan.ParseScheme(cfg.Scheme)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	choices := scheme.Choices(previousVersion, env.Now())
+	tags := func() ([]string, error) { return source.Tags(ctx) }
+	nextVersion, err := prompt(previousVersion, choices, tags, env.Stdin, env.Stderr)
+	if err != nil {
+		return err
+	}
+
+	// attach any build metadata, which is not part of the increment
+	if metadata := cmp.Or(opts.Metadata, cfg.Metadata); metadata != "" {
+		nextVersion, err = withMetadata(nextVersion, metadata, unfiltered, env.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(env.Stdout, "⚠️  v%s has build metadata: GitHub shows the + as %%2B in tag URLs, "+
+			"and Go modules ignore tags with build metadata\n", plan.VersionString(nextVersion))
+	}
+
+	rel := changelog.FileRelease{
+		Owner:      owner,
+		Repo:       repo,
+		Previous:   previousVersion,
+		Version:    nextVersion,
+		Date:       env.Now(),
+		Comparison: comparison,
 	}
 
-	// create draft URL embedding markdown changelog for next version...
+	// create draft embedding markdown changelog for next version...
+	notes := changelog.Options{Authors: cfg.Credits.Authors, Contributors: cfg.Credits.Contributors, Links: links}
+	if cfg.Credits.FirstTime {
+		notes.FirstTime = firstTimeContributors(ctx, source, previousRelease.GetTagName(), comparison)
+	}
 	body := strings.Join([]string{
-		RenderChangelogMarkdown(comparison),
-		comparisonURL(owner, repo, previousVersion, nextVersion),
+		changelog.RenderMarkdownWith(comparison, notes),
+		changelog.CompareURL(owner, repo, previousVersion, nextVersion),
 	}, "\n")
-	draftURL := draftReleaseURL(owner, repo, nextVersion, body)
+
+	// ...which the user may want to polish before anyone sees it
+	if opts.Edit {
+		body, err = editNotes(ctx, env, body)
+		if err != nil {
+			return err
+		}
+	}
+
+	// everything up to here has been read-only, so stop with a description of
+	// what would happen next.
+	if opts.DryRun {
+		var files []fileChange
+		if gitRepo != nil {
+			files, err = planReleaseFiles(root, cfg, opts, rel)
+			if err != nil {
+				return err
+			}
+		}
+		printPlan(env.Stdout, releasePlan{
+			Owner: owner, Repo: repo, Target: target,
+			Release: rel, Body: body, Files: files,
+			Hooks: cfg.Hooks, Opts: opts,
+		}, cfg)
+		return nil
+	}
+
+	hookRel := hookRelease{Owner: owner, Repo: repo, Previous: previousVersion, Next: nextVersion}
+	err = runHooks(ctx, env.Stdout, root, "after_version", cfg.Hooks.AfterVersion, hookRel)
+	if err != nil {
+		return err
+	}
+
+	// record the new version in files kept alongside the source, such as a
+	// changelog or package manifest, if the repository has any.
+	if gitRepo != nil {
+		if err := updateReleaseFiles(env, root, gitRepo, cfg, opts, rel); err != nil {
+			return err
+		}
+	}
+
+	// the web form URL is known before drafting, the API draft's only after
+	var draftURL string
+	if !opts.API {
+		draftURL = release.DraftURL(owner, repo, nextVersion, target, body)
+	}
+	hookRel.DraftURL = draftURL
+	err = runHooks(ctx, env.Stdout, root, "before_draft", cfg.Hooks.BeforeDraft, hookRel)
+	if err != nil {
+		return err
+	}
+
+	if opts.API {
+		draft, err := source.CreateRelease(ctx, release.NewDraft(nextVersion, target, body))
+		if err != nil {
+			return fmt.Errorf("failed to create draft release: %w", err)
+		}
+		fmt.Fprintln(env.Stdout, "✨ Created draft release on GitHub!")
+		draftURL = draft.GetHTMLURL()
+	}
 
 	// ...then send user to visit in their web browser!
-	if opts.NoOpen {
-		fmt.Println("To draft release, visit:", draftURL)
-	} else {
-		fmt.Println("✨ Drafting new release on GitHub!")
+	switch {
+	case opts.Offline:
+		// nothing was checked against GitHub, so show what would be posted
+		fmt.Fprintln(env.Stdout, body)
+		fmt.Fprintln(env.Stdout, "To draft release, visit:", draftURL)
+	case opts.NoOpen:
+		fmt.Fprintln(env.Stdout, "To draft release, visit:", draftURL)
+	default:
+		if !opts.API {
+			fmt.Fprintln(env.Stdout, "✨ Drafting new release on GitHub!")
+		}
 		logVerbose("Opening browser to: %s", draftURL)
-		err = browser.OpenURL(draftURL)
+		if err := env.OpenURL(draftURL); err != nil {
+			return err
+		}
+	}
+
+	// the release is drafted by now, so a failing hook can no longer stop it
+	hookRel.DraftURL = draftURL
+	err = runHooks(ctx, env.Stdout, root, "after_draft", cfg.Hooks.AfterDraft, hookRel)
+	if err != nil {
+		fmt.Fprintf(env.Stdout, "⚠️  %v\n", err)
+	}
+	return nil
+}
+
+// chooseBase looks back through the releases before latest, whose tag could
+// not be parsed as a version, for those which can. The user is told which
+// releases were skipped, and asked to confirm which to use as the base of the
+// next release.
+func chooseBase(ctx context.Context, env *environment, source release.Source, latest string, mode plan.TagMode) (*github.RepositoryRelease, *semver.Version, error) {
+	releases, err := source.Releases(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	bases, skipped := plan.Bases(releases, latest, mode)
+	for _, s := range skipped {
+		fmt.Fprintf(env.Stdout, "⚠️  Skipping release %s: %s\n", s.Tag, s.Reason)
+	}
+	if len(bases) == 0 {
+		return nil, nil, fmt.Errorf("no release with a %s semantic version tag found", mode)
+	}
+	base, err := promptBase(bases, env.Stdin, env.Stderr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return base.Release, base.Version, nil
+}
+
+// withMetadata adds build metadata rendered from tmpl to v, for a release of
+// the newest commit in comparison at time t.
+func withMetadata(v *semver.Version, tmpl string, comparison *github.CommitsComparison, t time.Time) (*semver.Version, error) {
+	var sha string
+	if len(comparison.Commits) > 0 {
+		sha = comparison.Commits[0].GetSHA()
+	} else {
+		sha = comparison.GetBaseCommit().GetSHA()
+	}
+	return plan.WithMetadata(v, tmpl, plan.NewMetadataInfo(sha, t))
+}
+
+// filterCommits applies filter to comparison, first filling in the files
+// touched by each commit from source if the filter matches on them.
+func filterCommits(ctx context.Context, source release.Source, filter changelog.Filter, comparison *github.CommitsComparison) (*github.CommitsComparison, []changelog.Hidden, error) {
+	if filter.NeedsFiles() {
+		for i, c := range comparison.Commits {
+			files, err := source.CommitFiles(ctx, c.GetSHA())
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to retrieve files of commit %s: %w", c.GetSHA(), err)
+			}
+			comparison.Commits[i].Files = make([]github.CommitFile, len(files))
+			for j, f := range files {
+				comparison.Commits[i].Files[j].Filename = github.String(f)
+			}
+		}
+	}
+	return filter.Apply(comparison)
+}
+
+// firstTimeContributors returns the contributors to comparison who have no
+// commits in the previous release. Contributors who can't be looked up are
+// left out, as welcoming a regular as a newcomer would be worse than not
+// welcoming a newcomer.
+func firstTimeContributors(ctx context.Context, source release.Source, previous string, comparison *github.CommitsComparison) []changelog.Author {
+	var first []changelog.Author
+	for _, a := range changelog.Contributors(comparison) {
+		contributed, err := source.HasContributed(ctx, previous, a)
 		if err != nil {
-			log.Fatal(err)
+			logVerbose("could not check if %s contributed before: %v", a, err)
+			continue
+		}
+		if !contributed {
+			first = append(first, a)
 		}
 	}
+	return first
 }
 
-// draftReleaseURL constructs a URL to open a new draft release on GitHub for
-// given owner/repo with a semver compatible tag based on the semver.Version in
-// the tag and title fields, and an encoded body payload to prepopulate the
-// form.
-func draftReleaseURL(owner, repo string, version *semver.Version, body string) string {
-	return fmt.Sprintf(
-		"https://github.com/%s/%s/releases/new?tag=v%s&title=v%s&body=%s",
-		owner, repo, version.String(), version.String(), url.QueryEscape(body),
-	)
+// workspace is the repository a command acts on, and where to find it locally.
+type workspace struct {
+	Owner, Repo string
+	Target      string          // ref on GitHub to release from, default branch if empty
+	Git         *git.Repository // local clone, nil if not needed
+	Root        string          // root of the local working tree, else working directory
+	Remote      string          // remote of the local clone pointing at GitHub
+	Config      *Config
+}
+
+// openWorkspace figures out the repository to act on: owner/repo if given,
+// otherwise detected from the local clone. The local clone is also opened if
+// local is set. If several GitHub repositories are found among its remotes,
+// the user is asked which to use if interactive, else the preferred one is.
+//
+// The local clone is found the way git finds it, so bump works from a
+// subdirectory, a linked worktree or a submodule too. Config and release
+// files are then relative to the root of its working tree.
+func openWorkspace(owner, repo string, local, interactive bool, opts Options, env *environment) (*workspace, error) {
+	ws := &workspace{
+		Owner:  owner,
+		Repo:   repo,
+		Target: opts.Target,
+		Root:   env.Dir,
+		Remote: "origin",
+		Config: &Config{},
+	}
+	detect := owner == "" || repo == ""
+	if !detect && !local {
+		return ws, nil
+	}
+
+	logVerbose("checking for local git repo")
+	var err error
+	ws.Git, err = remote.Open(env.Dir)
+	if err != nil {
+		if detect {
+			// probably just not in a git repo, no biggie
+			// just log what happened in verbose mode, and show usage
+			logVerbose("%v", err)
+			return nil, errUsage
+		}
+		return nil, err
+	}
+	ws.Root, err = remote.Root(ws.Git, env.Dir)
+	if err != nil {
+		return nil, err
+	}
+	logVerbose("found git repo at %s", ws.Root)
+
+	ws.Config, err = LoadConfig(ws.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := detectRepos(ws.Root, ws.Config.Remotes)
+	if detect {
+		if err == nil && len(repos) == 0 {
+			err = errors.New("no remote with a GitHub URL found")
+		}
+		if err != nil {
+			logVerbose("%v", err)
+			return nil, errUsage
+		}
+		r := repos[0]
+		if len(repos) > 1 && interactive {
+			if r, err = promptRepo(repos, env.Stdin, env.Stderr); err != nil {
+				return nil, err
+			}
+		}
+		ws.Owner, ws.Repo, ws.Remote = r.Owner, r.Name, r.Remote
+		logVerbose("detected .git repo with github remote %v/%v (%v)", ws.Owner, ws.Repo, ws.Remote)
+	} else if i := slices.IndexFunc(repos, func(r remote.Repo) bool {
+		return strings.EqualFold(r.Owner, owner) && strings.EqualFold(r.Name, repo)
+	}); i != -1 {
+		ws.Remote = repos[i].Remote
+	}
+
+	// default to releasing from the current branch, if it tracks a
+	// branch on GitHub (e.g. when working on a maintenance branch).
+	if ws.Target == "" {
+		ws.Target, err = remote.UpstreamBranch(ws.Root, ws.Remote)
+		if err != nil {
+			logVerbose("could not determine upstream branch: %v", err)
+		}
+		logVerbose("defaulting release target to upstream branch %q", ws.Target)
+	}
+	return ws, nil
+}
+
+// source returns where to read the release history of the workspace from:
+// the local clone in offline mode, otherwise GitHub.
+func (ws *workspace) source(opts Options, env *environment) release.Source {
+	if opts.Offline {
+		mode, _ := plan.ParseTagMode(ws.Config.TagMode) // checked by LoadConfig
+		return timedSource{release.NewLocalSource(ws.Git, ws.Owner, ws.Repo).WithRemote(ws.Remote).WithTagMode(mode)}
+	}
+	return timedSource{env.GitHub(ws.Owner, ws.Repo)}
+}
+
+// detectRepos wraps remote.Detector with timing info, finding the GitHub
+// repositories among the remotes of the local clone in order of preference.
+func detectRepos(path string, priority []string) ([]remote.Repo, error) {
+	defer timeTrack(time.Now(), "remote.Detector.Find()")
+	if len(priority) == 0 {
+		priority = remote.DefaultPriority
+	}
+	ssh, err := remote.LoadSSHConfig(remote.DefaultSSHConfigPath())
+	if err != nil {
+		logVerbose("could not read ssh config: %v", err)
+	}
+	return remote.Detector{Priority: priority, SSH: ssh}.Find(path)
 }
@@ -0,0 +1,530 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/changelog"
+	"github.com/mroth/bump/internal/bumptest"
+	"github.com/mroth/bump/release"
+)
+
+var originMaster = plumbing.NewRemoteReferenceName("origin", "master")
+
+// testRun holds a scripted run of the full program against a fake GitHub and
+// a local clone.
+type testRun struct {
+	fake   *bumptest.GitHub
+	repo   *bumptest.Repo
+	dir    string // working directory, if not the root of repo
+	stdout strings.Builder
+	opened []string
+}
+
+// newTestRun sets up a local clone of owner/repo, in sync with the fake, which
+// has a single v1.0.0 release followed by the sample commits.
+func newTestRun(t *testing.T) *testRun {
+	t.Helper()
+	tr := bumptest.NewRepo(t)
+	tr.AddRemote("origin", "https://github.com/owner/repo.git")
+	head := tr.Commit("a.txt", "a", "initial")
+	tr.SetRef(originMaster, head)
+
+	fake := bumptest.NewGitHub(t)
+	fake.Refs["master"] = head.String()
+	fake.Releases = []*github.RepositoryRelease{{
+		TagName:     github.String("v1.0.0"),
+		PublishedAt: &github.Timestamp{Time: bumptest.Now},
+	}}
+	fake.Comparisons["v1.0.0...HEAD"] = bumptest.CommitsComparisons["sample"]
+	return &testRun{fake: fake, repo: tr}
+}
+
+// run runs the program with the given scripted prompt input.
+func (r *testRun) run(opts Options, input string) error {
+	return run(context.Background(), "", "", opts, r.env(input))
+}
+
+// env returns the environment of the test run, with the given scripted prompt
+// input.
+func (r *testRun) env(input string) *environment {
+	return &environment{
+		Dir:    cmp.Or(r.dir, r.repo.Dir),
+		Stdin:  io.NopCloser(keystrokes{strings.NewReader(input)}),
+		Stdout: &r.stdout,
+		Stderr: io.Discard,
+		GitHub: func(owner, repo string) release.Source {
+			return release.NewGitHubSource(r.fake.APIClient(), owner, repo)
+		},
+		OpenURL: func(u string) error {
+			r.opened = append(r.opened, u)
+			return nil
+		},
+		Now: func() time.Time { return bumptest.Now },
+	}
+}
+
+// keystrokes delivers input a byte at a time like a terminal does, so input
+// for a prompt is not buffered up by the one before it.
+type keystrokes struct{ r io.Reader }
+
+func (k keystrokes) Read(p []byte) (int, error) {
+	return k.r.Read(p[:min(1, len(p))])
+}
+
+func TestRun(t *testing.T) {
+	sample := bumptest.CommitsComparisons["sample"]
+	links := &changelog.Links{Owner: "owner", Repo: "repo"}
+	wantBody := changelog.RenderMarkdownWith(sample, changelog.Options{Links: links}) + "\n" +
+		"https://github.com/owner/repo/compare/v1.0.0...v1.1.0"
+
+	t.Run("web form", func(t *testing.T) {
+		r := newTestRun(t)
+		if err := r.run(Options{}, "j\n"); err != nil { // down to minor, select
+			t.Fatal(err)
+		}
+		if !strings.Contains(r.stdout.String(), changelog.RenderScreen(sample)) {
+			t.Errorf("changelog not shown, got output:\n%s", r.stdout.String())
+		}
+		want := release.DraftURL("owner", "repo", semver.MustParse("1.1.0"), "", wantBody)
+		if len(r.opened) != 1 || r.opened[0] != want {
+			t.Errorf("opened %q, want %q", r.opened, want)
+		}
+	})
+
+	t.Run("no open", func(t *testing.T) {
+		r := newTestRun(t)
+		if err := r.run(Options{NoOpen: true}, "\n"); err != nil {
+			t.Fatal(err)
+		}
+		want := release.DraftURL("owner", "repo", semver.MustParse("1.0.1"), "", strings.Replace(wantBody, "v1.1.0", "v1.0.1", 1))
+		if !strings.Contains(r.stdout.String(), "To draft release, visit: "+want) || len(r.opened) != 0 {
+			t.Errorf("want URL %q printed and not opened, got output:\n%s", want, r.stdout.String())
+		}
+	})
+
+	t.Run("custom version", func(t *testing.T) {
+		r := newTestRun(t)
+		r.fake.Tags = []string{"v1.0.0", "v3.0.0-rc.1"}
+		if err := r.run(Options{API: true}, "jjj\n3.0.0\n"); err != nil { // down to custom…, select, type
+			t.Fatal(err)
+		}
+		if len(r.fake.Created) != 1 || r.fake.Created[0].GetTagName() != "v3.0.0" {
+			t.Errorf("want v3.0.0 created, got %+v", r.fake.Created)
+		}
+	})
+
+	t.Run("build metadata", func(t *testing.T) {
+		r := newTestRun(t)
+		if err := r.run(Options{API: true, Metadata: "sha.{{.ShortSHA}}"}, "j\n"); err != nil {
+			t.Fatal(err)
+		}
+		if len(r.fake.Created) != 1 || r.fake.Created[0].GetTagName() != "v1.1.0+sha.a1b2c3d" {
+			t.Errorf("want v1.1.0+sha.a1b2c3d created, got %+v", r.fake.Created)
+		}
+		if !strings.Contains(r.stdout.String(), "v1.1.0+sha.a1b2c3d has build metadata") {
+			t.Errorf("want build metadata warning, got output:\n%s", r.stdout.String())
+		}
+	})
+
+	t.Run("calver", func(t *testing.T) {
+		r := newTestRun(t)
+		r.repo.Commit(ConfigFileName, `{"scheme": "YY.0M.MICRO"}`, "add config")
+		r.fake.Releases[0].TagName = github.String("v25.07.1")
+		r.fake.Comparisons["v25.07.1...HEAD"] = sample
+		if err := r.run(Options{API: true, SkipChecks: true}, "\n"); err != nil {
+			t.Fatal(err)
+		}
+		if len(r.fake.Created) != 1 || r.fake.Created[0].GetTagName() != "v25.07.2" {
+			t.Fatalf("want v25.07.2 created, got %+v", r.fake.Created)
+		}
+		if want := "https://github.com/owner/repo/compare/v25.07.1...v25.07.2"; !strings.HasSuffix(r.fake.Created[0].GetBody(), want) {
+			t.Errorf("created body does not end in %s:\n%s", want, r.fake.Created[0].GetBody())
+		}
+	})
+
+	t.Run("non-semver latest release", func(t *testing.T) {
+		r := newTestRun(t)
+		r.fake.Releases = append([]*github.RepositoryRelease{{
+			TagName:     github.String("nightly"),
+			PublishedAt: &github.Timestamp{Time: bumptest.Now.Add(time.Hour)},
+		}}, r.fake.Releases...)
+		if err := r.run(Options{API: true}, "\nj\n"); err != nil { // confirm v1.0.0, then minor
+			t.Fatal(err)
+		}
+		if !strings.Contains(r.stdout.String(), `Skipping release nightly: tag "nightly" is not a semantic version`) {
+			t.Errorf("skipped release not explained, got output:\n%s", r.stdout.String())
+		}
+		if len(r.fake.Created) != 1 || r.fake.Created[0].GetBody() != wantBody {
+			t.Errorf("want v1.1.0 created following v1.0.0, got %+v", r.fake.Created)
+		}
+	})
+
+	t.Run("fork", func(t *testing.T) {
+		r := newTestRun(t)
+		r.repo.AddRemote("upstream", "git@github.com:parent/repo.git")
+		if err := r.run(Options{NoOpen: true}, "j\nj\n"); err != nil { // down to origin, select, then minor
+			t.Fatal(err)
+		}
+		want := release.DraftURL("owner", "repo", semver.MustParse("1.1.0"), "", wantBody)
+		if !strings.Contains(r.stdout.String(), "To draft release, visit: "+want) {
+			t.Errorf("want URL %q printed, got output:\n%s", want, r.stdout.String())
+		}
+	})
+
+	t.Run("api", func(t *testing.T) {
+		r := newTestRun(t)
+		if err := r.run(Options{API: true}, "j\n"); err != nil {
+			t.Fatal(err)
+		}
+		if len(r.fake.Created) != 1 {
+			t.Fatalf("want 1 release created, got %d", len(r.fake.Created))
+		}
+		created := r.fake.Created[0]
+		if created.GetTagName() != "v1.1.0" || !created.GetDraft() || created.GetBody() != wantBody {
+			t.Errorf("created release = %+v", created)
+		}
+		if len(r.opened) != 1 || !strings.HasSuffix(r.opened[0], "/releases/tag/untagged-1") {
+			t.Errorf("want created draft opened, got %q", r.opened)
+		}
+	})
+
+	t.Run("credits", func(t *testing.T) {
+		r := newTestRun(t)
+		r.repo.Commit(ConfigFileName, `{"credits": {"authors": true, "contributors": true, "first_time": true}}`, "add config")
+		r.fake.Contributors = []string{"alicej", "bobsmith", "carol@example.com", "david@example.com",
+			"eva@example.com", "frank@example.com", "grace@example.com"}
+		if err := r.run(Options{API: true, SkipChecks: true}, "j\n"); err != nil {
+			t.Fatal(err)
+		}
+		if len(r.fake.Created) != 1 {
+			t.Fatalf("want 1 release created, got %d", len(r.fake.Created))
+		}
+		body := r.fake.Created[0].GetBody()
+		for _, want := range []string{
+			"- feat: add new user authentication system [a1b2c3d](https://github.com/owner/repo/commit/a1b2c3d4e5f6789012345678901234567890abcd) by @alicej\n",
+			"## Contributors\n",
+			"- @danalee made their first contribution",
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("body missing %q:\n%s", want, body)
+			}
+		}
+		if strings.Count(body, "first contribution") != 1 {
+			t.Errorf("want only @danalee welcomed:\n%s", body)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		r := newTestRun(t)
+		r.repo.Commit(ConfigFileName, `{"filter": {"exclude": [{"types": ["test", "chore"]}, {"paths": ["docs/"]}]}}`, "add config")
+		for _, c := range bumptest.CommitsComparisons["sample"].Commits {
+			r.fake.CommitFiles[c.GetSHA()] = []string{"main.go", "docs/api.md"}
+		}
+		docs := bumptest.CommitsComparisons["sample"].Commits[2].GetSHA()
+		r.fake.CommitFiles[docs] = []string{"docs/api.md"}
+		if err := r.run(Options{API: true, SkipChecks: true}, "j\n"); err != nil {
+			t.Fatal(err)
+		}
+		if want := "(3 commits hidden: 1 only touching docs/, 2 test/chore commits)"; !strings.Contains(r.stdout.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, r.stdout.String())
+		}
+		body := r.fake.Created[0].GetBody()
+		for _, hidden := range []string{"docs:", "test:", "chore:"} {
+			if strings.Contains(body, hidden) {
+				t.Errorf("body has hidden %q commit:\n%s", hidden, body)
+			}
+		}
+	})
+
+	t.Run("review", func(t *testing.T) {
+		r := newTestRun(t)
+		r.fake.CommitFiles[sample.Commits[1].GetSHA()] = []string{"worker.go"}
+		input := "\n\n" + // leave out the first commit
+			"j\nj\n" + // show the files of the second
+			strings.Repeat("j", len(sample.Commits)-1) + "\n" + // done
+			"j\n" // minor
+		if err := r.run(Options{API: true, Review: true, SkipChecks: true}, input); err != nil {
+			t.Fatal(err)
+		}
+		if len(r.fake.Created) != 1 || r.fake.Created[0].GetTagName() != "v1.1.0" {
+			t.Fatalf("want v1.1.0 created, got %+v", r.fake.Created)
+		}
+		body := r.fake.Created[0].GetBody()
+		if strings.Contains(body, "feat: add new user authentication system") {
+			t.Errorf("body has the commit left out:\n%s", body)
+		}
+		if !strings.Contains(body, "fix: resolve memory leak in background worker") {
+			t.Errorf("body missing the commit left in:\n%s", body)
+		}
+	})
+
+	t.Run("release files", func(t *testing.T) {
+		r := newTestRun(t)
+		r.repo.Commit("VERSION", "1.0.0\n", "add version file")
+		r.repo.Commit(ConfigFileName, `{"version_files": [{"path": "VERSION"}]}`, "add config")
+		cfg, err := r.repo.Config()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.User.Name, cfg.User.Email = "Releaser", "releaser@example.com"
+		if err := r.repo.SetConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		versionPath := filepath.Join(r.repo.Dir, "VERSION")
+		if err := os.Chmod(versionPath, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		opts := Options{ChangelogFile: "CHANGELOG.md", Commit: true, NoOpen: true, SkipChecks: true}
+		if err := r.run(opts, "j\n"); err != nil {
+			t.Fatal(err)
+		}
+		fi, err := os.Stat(versionPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fi.Mode().Perm(); got != 0600 {
+			t.Errorf("VERSION mode after update = %v, want kept at 0600", got)
+		}
+		head, err := r.repo.Head()
+		if err != nil {
+			t.Fatal(err)
+		}
+		commit, err := r.repo.CommitObject(head.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if commit.Message != "Release v1.1.0" || commit.Author.Name != "Releaser" {
+			t.Errorf("HEAD commit = %q by %s, want release commit", commit.Message, commit.Author.Name)
+		}
+		f, err := commit.File("CHANGELOG.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := f.Contents()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(got, "## [1.1.0] - 2025-07-22") {
+			t.Errorf("committed CHANGELOG.md missing release section:\n%s", got)
+		}
+		if f, err = commit.File("VERSION"); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ = f.Contents(); got != "1.1.0\n" {
+			t.Errorf("committed VERSION = %q, want 1.1.0", got)
+		}
+		if !strings.Contains(r.stdout.String(), "-1.0.0\n+1.1.0\n") {
+			t.Errorf("version file diff not shown, got output:\n%s", r.stdout.String())
+		}
+	})
+
+	t.Run("release files with others staged", func(t *testing.T) {
+		r := newTestRun(t)
+		r.repo.Commit("VERSION", "1.0.0\n", "add version file")
+		r.repo.Commit(ConfigFileName, `{"version_files": [{"path": "VERSION"}]}`, "add config")
+		head, err := r.repo.Head()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.repo.Write("notes.txt", "not for the release\n")
+		wt, err := r.repo.Worktree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add("notes.txt"); err != nil {
+			t.Fatal(err)
+		}
+
+		err = r.run(Options{API: true, Commit: true, SkipChecks: true}, "j\n")
+		if err == nil || !strings.Contains(err.Error(), "staged notes.txt") {
+			t.Fatalf("want error about staged notes.txt, got %v", err)
+		}
+		if after, _ := r.repo.Head(); after.Hash() != head.Hash() {
+			t.Errorf("HEAD moved to %v, want nothing committed", after.Hash())
+		}
+		if len(r.fake.Created) != 0 {
+			t.Errorf("want no release created, got %d", len(r.fake.Created))
+		}
+	})
+
+	t.Run("worktree subdirectory", func(t *testing.T) {
+		r := newTestRun(t)
+		r.repo.Commit("VERSION", "1.0.0\n", "add version file")
+		r.repo.Commit(ConfigFileName, `{"version_files": [{"path": "VERSION"}]}`, "add config")
+		wt := r.repo.AddWorktree("feature")
+		r.dir = filepath.Join(wt, "docs")
+		if err := os.Mkdir(r.dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := r.run(Options{NoOpen: true, SkipChecks: true}, "j\n"); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(wt, "VERSION"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "1.1.0\n" {
+			t.Errorf("worktree VERSION = %q, want 1.1.0", got)
+		}
+		want := release.DraftURL("owner", "repo", semver.MustParse("1.1.0"), "", wantBody)
+		if !strings.Contains(r.stdout.String(), "To draft release, visit: "+want) {
+			t.Errorf("want URL %q printed, got output:\n%s", want, r.stdout.String())
+		}
+	})
+
+	t.Run("hooks", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("hook commands in test use sh")
+		}
+		r := newTestRun(t)
+		r.repo.Commit(ConfigFileName, `{"hooks": {
+			"after_version": ["echo $BUMP_PREVIOUS_VERSION $BUMP_NEXT_VERSION > version.out"],
+			"after_draft": ["echo $BUMP_OWNER/$BUMP_REPO $BUMP_TAG $BUMP_DRAFT_URL > draft.out"]
+		}}`, "add config")
+		if err := r.run(Options{SkipChecks: true}, "j\n"); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(r.repo.Dir, "version.out"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "1.0.0 1.1.0\n" {
+			t.Errorf("after_version hook got %q", got)
+		}
+		got, err = os.ReadFile(filepath.Join(r.repo.Dir, "draft.out"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "owner/repo v1.1.0 " + r.opened[0] + "\n"; string(got) != want {
+			t.Errorf("after_draft hook got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("hook aborts", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("hook commands in test use sh")
+		}
+		r := newTestRun(t)
+		r.repo.Commit(ConfigFileName, `{"hooks": {"before_draft": ["echo tests failed; exit 2"]}}`, "add config")
+		err := r.run(Options{SkipChecks: true}, "j\n")
+		if err == nil || !strings.Contains(err.Error(), "before_draft hook") {
+			t.Errorf("want before_draft hook error, got %v", err)
+		}
+		if !strings.Contains(r.stdout.String(), "tests failed\n") {
+			t.Errorf("hook output not shown, got output:\n%s", r.stdout.String())
+		}
+		if len(r.opened) != 0 {
+			t.Errorf("should not have opened anything, got %q", r.opened)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		r := newTestRun(t)
+		r.repo.Commit("VERSION", "1.0.0\n", "add version file")
+		head := r.repo.Commit(ConfigFileName, `{
+			"version_files": [{"path": "VERSION"}],
+			"hooks": {"after_version": ["touch hooked"]}
+		}`, "add config")
+
+		opts := Options{DryRun: true, API: true, ChangelogFile: "CHANGELOG.md", Commit: true, SkipChecks: true}
+		if err := r.run(opts, "j\n"); err != nil {
+			t.Fatal(err)
+		}
+		out := r.stdout.String()
+		for _, want := range []string{
+			"run after_version hook: touch hooked",
+			"-1.0.0\n      +1.1.0",
+			"add a 1.1.0 section to CHANGELOG.md",
+			`commit VERSION, CHANGELOG.md as "Release v1.1.0"`,
+			"POST /repos/owner/repo/releases (draft v1.1.0 from default branch)",
+			wantBody,
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("plan missing %q, got output:\n%s", want, out)
+			}
+		}
+
+		if len(r.opened) != 0 || len(r.fake.Created) != 0 {
+			t.Errorf("should not have drafted anything, opened %q created %d", r.opened, len(r.fake.Created))
+		}
+		for _, file := range []string{"hooked", "CHANGELOG.md"} {
+			if _, err := os.Stat(filepath.Join(r.repo.Dir, file)); !os.IsNotExist(err) {
+				t.Errorf("%s should not exist, got %v", file, err)
+			}
+		}
+		if got, _ := os.ReadFile(filepath.Join(r.repo.Dir, "VERSION")); string(got) != "1.0.0\n" {
+			t.Errorf("VERSION was modified to %q", got)
+		}
+		if ref, _ := r.repo.Head(); ref.Hash() != head {
+			t.Errorf("HEAD moved to %v", ref.Hash())
+		}
+	})
+
+	t.Run("edit notes", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("editor script in test uses sh")
+		}
+		script := filepath.Join(t.TempDir(), "editor")
+		err := os.WriteFile(script, []byte("#!/bin/sh\nsed 's/^## Changelog$/## Highlights/' \"$1\" > \"$1.tmp\" && mv \"$1.tmp\" \"$1\"\n"), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("VISUAL", script)
+
+		r := newTestRun(t)
+		if err := r.run(Options{API: true, Edit: true}, "j\n"); err != nil {
+			t.Fatal(err)
+		}
+		if len(r.fake.Created) != 1 {
+			t.Fatalf("want 1 release created, got %d", len(r.fake.Created))
+		}
+		want := strings.Replace(wantBody, "## Changelog", "## Highlights", 1)
+		if got := r.fake.Created[0].GetBody(); got != want {
+			t.Errorf("created body = %q, want %q", got, want)
+		}
+
+		r = newTestRun(t)
+		t.Setenv("VISUAL", ": >") // empties the file
+		if err := r.run(Options{API: true, Edit: true}, "j\n"); !errors.Is(err, errEmptyNotes) {
+			t.Errorf("emptied notes: want errEmptyNotes, got %v", err)
+		}
+		if len(r.fake.Created) != 0 {
+			t.Errorf("should not have created a release, got %d", len(r.fake.Created))
+		}
+	})
+
+	t.Run("preflight blocked", func(t *testing.T) {
+		r := newTestRun(t)
+		r.repo.Commit("b.txt", "b", "not pushed")
+		err := r.run(Options{}, "\n")
+		if !errors.Is(err, errPreflightBlocked) {
+			t.Errorf("want errPreflightBlocked, got %v", err)
+		}
+		if len(r.opened) != 0 {
+			t.Errorf("should not have opened anything, got %q", r.opened)
+		}
+	})
+
+	t.Run("not in a repo", func(t *testing.T) {
+		r := newTestRun(t)
+		r.repo.Dir = t.TempDir()
+		if err := r.run(Options{}, "\n"); !errors.Is(err, errUsage) {
+			t.Errorf("want errUsage, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mroth/bump/plan"
+)
+
+// runNext prints the version the next release of owner/repo would be, and
+// nothing else, for use in scripts such as $(bump next --auto). The version
+// is the one suggested by the commits since the latest release with
+// opts.Auto, or the increment named by opts.Increment. There are no prompts.
+func runNext(ctx context.Context, owner, repo string, opts Options, env *environment) error {
+	if opts.Auto == (opts.Increment != "") {
+		return errors.New("next needs one of --auto or --increment <name>")
+	}
+
+	ws, err := openWorkspace(owner, repo, opts.Offline, false, opts, env)
+	if err != nil {
+		return err
+	}
+	s, err := getStatus(ctx, ws.source(opts, env), ws.Config, ws.Target, env.Now())
+	if err != nil {
+		return err
+	}
+
+	next := s.Next
+	if opts.Increment != "" {
+		i := slices.IndexFunc(s.Choices, func(c plan.Choice) bool { return c.Name == opts.Increment })
+		if i == -1 {
+			var names []string
+			for _, c := range s.Choices {
+				names = append(names, c.Name)
+			}
+			return fmt.Errorf("unknown increment %q (want %s)", opts.Increment, strings.Join(names, ", "))
+		}
+		next = s.Choices[i]
+	}
+	logVerbose("next version is the %s increment of %v", next.Name, s.Version)
+
+	version := &next.Version
+	if metadata := cmp.Or(opts.Metadata, ws.Config.Metadata); metadata != "" {
+		version, err = withMetadata(version, metadata, s.Comparison, env.Now())
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(env.Stdout, opts.Prefix+plan.VersionString(version))
+	return nil
+}
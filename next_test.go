@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/internal/bumptest"
+)
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		nightly bool // latest release is not a version
+		want    string
+		wantErr bool
+	}{
+		{"auto", Options{Auto: true}, false, "1.1.0\n", false},
+		{"increment", Options{Increment: "major"}, false, "2.0.0\n", false},
+		{"prefix", Options{Auto: true, Prefix: "v"}, false, "v1.1.0\n", false},
+		{"metadata", Options{Increment: "patch", Metadata: "sha.{{.ShortSHA}}"}, false, "1.0.1+sha.a1b2c3d\n", false},
+		{"non-semver latest release", Options{Auto: true}, true, "1.1.0\n", false},
+		{"unknown increment", Options{Increment: "huge"}, false, "", true},
+		{"no strategy", Options{}, false, "", true},
+		{"both strategies", Options{Auto: true, Increment: "minor"}, false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRun(t)
+			if tt.nightly {
+				r.fake.Releases = append([]*github.RepositoryRelease{{
+					TagName:     github.String("nightly"),
+					PublishedAt: &github.Timestamp{Time: bumptest.Now.Add(time.Hour)},
+				}}, r.fake.Releases...)
+			}
+			err := runNext(context.Background(), "", "", tt.opts, r.env(""))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runNext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := r.stdout.String(); got != tt.want {
+				t.Errorf("runNext() printed %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,23 +4,72 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 )
 
 const usageText = `Usage: bump <owner> <repo>
+       bump next [<owner> <repo>] --auto | --increment <name>
+       bump status [<owner> <repo> | --org <name> | --repos <file>]
 
 If you are in a git repository that has been cloned from GitHub, owner and
-repo args can be omitted, in which case they will be inferred from the remote
-origin.
+repo args can be omitted, in which case they will be inferred from its
+remotes, preferring upstream over origin.
+
+Commands:
+    next                Print the next version and nothing else, for scripts,
+                        e.g. $(bump next --auto).
+    status              Show the changes since the latest release and the
+                        suggested next version, without drafting anything.
+                        Exits 3 if there are unreleased changes. With --org
+                        or --repos, show a table of many repositories, then
+                        pick any to draft releases for.
 
 Flags:
+    --api               Create the draft release via the GitHub API instead of
+                        the web form. Requires $GITHUB_TOKEN with write access.
+    --auto              For next, use the version suggested by the commits
+                        since the latest release.
+    --changelog-file <path>
+                        Add the release to a Keep a Changelog format file in
+                        the local repository, e.g. CHANGELOG.md.
+    --commit            Commit files changed for the release, rather than
+                        leaving them for review.
+    --dry-run           Show what would be done to draft the release, without
+                        changing, creating or opening anything.
+    --edit              Edit the release notes in $VISUAL or $EDITOR before
+                        drafting. Saving an empty file aborts the release.
+    --increment <name>  For next, use the named increment: patch, minor or
+                        major, or micro or calendar for CalVer.
+    --jobs <n>          Repositories for status to check at once, 8 if 0 (default 8).
+    --metadata <text>   Build metadata to add to the version, e.g. build.42.
+                        May use {{.SHA}}, {{.ShortSHA}} and {{.Date}} of the
+                        release, e.g. sha.{{.ShortSHA}}.
     --no-open           Do not automatically open publish URL in browser.
+    --offline           Use only the local clone, making no GitHub API calls.
+    --org <name>        Check all repositories of a GitHub organization for
+                        status.
+    --prefix <text>     Prefix for the version printed by next, e.g. v.
+    --repos <file>      Check the repositories listed in a file for status,
+                        one owner/repo per line.
+    --review            Look through every commit before choosing the next
+                        version, and pick those to list in the release notes.
+    --skip-checks       Skip preflight checks of the local working copy.
+    --target <ref>      Branch or commit SHA to draft the release from.
+                        Defaults to the current branch if it tracks a
+                        branch on GitHub, otherwise the default branch.
     --verbose, -v       Verbose output.
     --version           Print version and exit.
     --help              Print help and exit.
 
 Environment:
+    $BUMP_API           Global default for --api
+    $BUMP_COMMIT        Global default for --commit
+    $BUMP_EDIT          Global default for --edit
     $BUMP_NO_OPEN       Global default for --no-open
+    $BUMP_OFFLINE       Global default for --offline
+    $BUMP_REVIEW        Global default for --review
+    $BUMP_SKIP_CHECKS   Global default for --skip-checks
     $BUMP_VERBOSE       Global default for --verbose
     $GITHUB_TOKEN       Optional, will use if present to access private repos
 `
@@ -34,22 +83,50 @@ func usage() {
 //
 // The zero value represents the program defaults.
 type Options struct {
-	NoOpen  bool // dont auto-open the final URL in browser
-	Verbose bool // verbose output requested
+	API           bool   // create draft release via API rather than web form
+	Auto          bool   // use the suggested increment for next
+	ChangelogFile string // changelog file to update, none if empty
+	Commit        bool   // commit changed files rather than leave for review
+	DryRun        bool   // only show what would be done
+	Edit          bool   // edit release notes in user's editor before drafting
+	Increment     string // increment to use for next, such as "minor"
+	Jobs          int    // repos to check concurrently for status, default if 0
+	Metadata      string // build metadata template for next version, if any
+	NoOpen        bool   // dont auto-open the final URL in browser
+	Offline       bool   // use local git clone only, no GitHub API calls
+	Org           string // organization whose repos to check for status
+	Prefix        string // prefix for the version printed by next
+	Repos         string // file listing repos to check for status
+	Review        bool   // review every commit before choosing the version
+	SkipChecks    bool   // skip preflight checks of local working copy
+	Target        string // branch or sha to release from, default branch if empty
+	Verbose       bool   // verbose output requested
 }
 
 // Environment variable "key" constants used to map to Options settings.
 const (
-	EnvKeyNoOpen  = "BUMP_NO_OPEN"
-	EnvKeyVerbose = "BUMP_VERBOSE"
+	EnvKeyAPI        = "BUMP_API"
+	EnvKeyCommit     = "BUMP_COMMIT"
+	EnvKeyEdit       = "BUMP_EDIT"
+	EnvKeyNoOpen     = "BUMP_NO_OPEN"
+	EnvKeyOffline    = "BUMP_OFFLINE"
+	EnvKeyReview     = "BUMP_REVIEW"
+	EnvKeySkipChecks = "BUMP_SKIP_CHECKS"
+	EnvKeyVerbose    = "BUMP_VERBOSE"
 )
 
 // NewOptionsFromEnv will return a populated Options struct with any settings
 // defined via environment variables applied.
 func NewOptionsFromEnv() *Options {
 	return &Options{
-		NoOpen:  getBoolEnv(EnvKeyNoOpen),
-		Verbose: getBoolEnv(EnvKeyVerbose),
+		API:        getBoolEnv(EnvKeyAPI),
+		Commit:     getBoolEnv(EnvKeyCommit),
+		Edit:       getBoolEnv(EnvKeyEdit),
+		NoOpen:     getBoolEnv(EnvKeyNoOpen),
+		Offline:    getBoolEnv(EnvKeyOffline),
+		Review:     getBoolEnv(EnvKeyReview),
+		SkipChecks: getBoolEnv(EnvKeySkipChecks),
+		Verbose:    getBoolEnv(EnvKeyVerbose),
 	}
 }
 
@@ -79,7 +156,23 @@ func ParseFlags(opts *Options, args []string) (Options, *flag.FlagSet) {
 	var newOpts Options
 	var flags flag.FlagSet
 
+	flags.BoolVar(&newOpts.API, "api", opts.API, "")
+	flags.BoolVar(&newOpts.Auto, "auto", opts.Auto, "")
+	flags.StringVar(&newOpts.ChangelogFile, "changelog-file", opts.ChangelogFile, "")
+	flags.BoolVar(&newOpts.Commit, "commit", opts.Commit, "")
+	flags.BoolVar(&newOpts.DryRun, "dry-run", opts.DryRun, "")
+	flags.BoolVar(&newOpts.Edit, "edit", opts.Edit, "")
+	flags.StringVar(&newOpts.Increment, "increment", opts.Increment, "")
+	flags.IntVar(&newOpts.Jobs, "jobs", opts.Jobs, "")
+	flags.StringVar(&newOpts.Metadata, "metadata", opts.Metadata, "")
 	flags.BoolVar(&newOpts.NoOpen, "no-open", opts.NoOpen, "")
+	flags.BoolVar(&newOpts.Offline, "offline", opts.Offline, "")
+	flags.StringVar(&newOpts.Org, "org", opts.Org, "")
+	flags.StringVar(&newOpts.Prefix, "prefix", opts.Prefix, "")
+	flags.StringVar(&newOpts.Repos, "repos", opts.Repos, "")
+	flags.BoolVar(&newOpts.Review, "review", opts.Review, "")
+	flags.BoolVar(&newOpts.SkipChecks, "skip-checks", opts.SkipChecks, "")
+	flags.StringVar(&newOpts.Target, "target", opts.Target, "")
 	flags.BoolVar(&newOpts.Verbose, "verbose", opts.Verbose, "")
 	flags.BoolVar(&newOpts.Verbose, "v", opts.Verbose, "")
 	version := flags.Bool("version", false, "")
@@ -96,11 +189,28 @@ func ParseFlags(opts *Options, args []string) (Options, *flag.FlagSet) {
 	return newOpts, &flags
 }
 
-// ParseAll rolls up all CLI option parsing curently needed for main()
-func ParseAll() (owner, repo string, opts Options) {
-	opts, flags := ParseFlags(NewOptionsFromEnv(), os.Args[1:])
-	owner = flags.Arg(0)
-	repo = flags.Arg(1)
+// commands are the subcommands understood in place of drafting a release.
+var commands = []string{"next", "status"}
+
+// ParseAll rolls up all CLI option parsing curently needed for main(). cmd is
+// the subcommand requested, or empty to draft a release.
+func ParseAll() (cmd, owner, repo string, opts Options) {
+	args := os.Args[1:]
+	if len(args) > 0 && slices.Contains(commands, args[0]) {
+		cmd, args = args[0], args[1:]
+	}
+	opts, flags := ParseFlags(NewOptionsFromEnv(), args)
+
+	// flag stops at the first positional argument, so carry on parsing after
+	// each one, allowing flags after owner and repo, as in bump next
+	// mroth bump --auto.
+	var positional []string
+	for flags.NArg() > 0 {
+		positional = append(positional, flags.Arg(0))
+		opts, flags = ParseFlags(&opts, flags.Args()[1:])
+	}
+	positional = append(positional, "", "")
+	owner, repo = positional[0], positional[1]
 	return
 }
 
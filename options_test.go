@@ -45,6 +45,14 @@ func TestOptionsPrecedence(t *testing.T) {
 				Verbose: true,
 			},
 		},
+		{
+			desc: "--offline arg with --target",
+			args: []string{"--offline", "--target", "release/1.x"},
+			expected: Options{
+				Offline: true,
+				Target:  "release/1.x",
+			},
+		},
 		{
 			desc: "env arg bool true format",
 			env:  []string{EnvKeyVerbose + "=true"},
@@ -96,6 +104,36 @@ func TestOptionsPrecedence(t *testing.T) {
 	resetEnviron(originalEnv)
 }
 
+func TestParseAll(t *testing.T) {
+	testCases := []struct {
+		args                         []string
+		wantCmd, wantOwner, wantRepo string
+		wantOpts                     Options
+	}{
+		{[]string{"mroth", "bump"}, "", "mroth", "bump", Options{}},
+		{[]string{"--api", "mroth", "bump"}, "", "mroth", "bump", Options{API: true}},
+		{[]string{"next", "--auto", "mroth", "bump"}, "next", "mroth", "bump", Options{Auto: true}},
+		{[]string{"next", "mroth", "bump", "--auto", "--prefix", "v"}, "next", "mroth", "bump", Options{Auto: true, Prefix: "v"}},
+		{[]string{"status", "mroth", "--verbose", "bump"}, "status", "mroth", "bump", Options{Verbose: true}},
+		{[]string{"next", "--increment", "minor"}, "next", "", "", Options{Increment: "minor"}},
+	}
+	originalArgs, originalEnv := os.Args, os.Environ()
+	defer func() { os.Args = originalArgs; resetEnviron(originalEnv) }()
+	os.Clearenv()
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			os.Args = append([]string{"bump"}, tc.args...)
+			cmd, owner, repo, opts := ParseAll()
+			if cmd != tc.wantCmd || owner != tc.wantOwner || repo != tc.wantRepo {
+				t.Errorf("ParseAll() = %q, %q, %q, want %q, %q, %q", cmd, owner, repo, tc.wantCmd, tc.wantOwner, tc.wantRepo)
+			}
+			if opts != tc.wantOpts {
+				t.Errorf("ParseAll() opts = %+v, want %+v", opts, tc.wantOpts)
+			}
+		})
+	}
+}
+
 // resetEnviron cleasrs and then sets the environment to match a []string of
 // key=value pairs, which happens to be exactly what os.Environ() from the
 // standard library provides us, but with no built in way set back using the
@@ -0,0 +1,97 @@
+package plan
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/v29/github"
+)
+
+// TagMode controls how strictly release tags are parsed as versions.
+type TagMode string
+
+// Possible TagMode values.
+const (
+	// Lenient accepts anything semver.NewVersion does, such as "v1.2" for
+	// 1.2.0 or zero padded CalVer versions like "v26.01.3". The default.
+	Lenient TagMode = "lenient"
+	// Strict accepts only complete semantic versions, with an optional "v"
+	// prefix, such as "v1.2.0".
+	Strict TagMode = "strict"
+)
+
+// ParseTagMode returns the TagMode named by s, defaulting to Lenient if
+// s is empty.
+func ParseTagMode(s string) (TagMode, error) {
+	switch m := TagMode(s); m {
+	case "":
+		return Lenient, nil
+	case Lenient, Strict:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown tag mode %q (want lenient or strict)", s)
+	}
+}
+
+// Parse parses a release tag as a version.
+func (m TagMode) Parse(tag string) (*semver.Version, error) {
+	if m == Strict {
+		if _, err := semver.StrictNewVersion(strings.TrimPrefix(tag, "v")); err != nil {
+			return nil, fmt.Errorf("tag %q is not a semantic version: %w", tag, err)
+		}
+	}
+	v, err := semver.NewVersion(tag)
+	if err != nil {
+		return nil, fmt.Errorf("tag %q is not a semantic version", tag)
+	}
+	return v, nil
+}
+
+// Base is a release which the next release can follow.
+type Base struct {
+	Release *github.RepositoryRelease
+	Version *semver.Version
+}
+
+// Skipped is a release passed over by Bases, and why.
+type Skipped struct {
+	Tag    string
+	Reason string
+}
+
+// Bases walks back through releases from the one tagged latest, in order of
+// publication, returning those whose tags parse as versions with mode, newest
+// first, along with the releases skipped because their tags did not. Drafts
+// and prereleases are ignored, like for the latest release on GitHub.
+func Bases(releases []*github.RepositoryRelease, latest string, mode TagMode) ([]Base, []Skipped) {
+	sorted := slices.Clone(releases)
+	slices.SortStableFunc(sorted, func(a, b *github.RepositoryRelease) int {
+		return b.GetPublishedAt().Compare(a.GetPublishedAt().Time)
+	})
+	if i := slices.IndexFunc(sorted, func(r *github.RepositoryRelease) bool {
+		return r.GetTagName() == latest
+	}); i != -1 {
+		sorted = sorted[i:]
+	}
+
+	var (
+		bases   []Base
+		skipped []Skipped
+	)
+	for _, r := range sorted {
+		if r.GetDraft() || r.GetPrerelease() {
+			continue
+		}
+		v, err := mode.Parse(r.GetTagName())
+		if err != nil {
+			if len(bases) == 0 {
+				skipped = append(skipped, Skipped{r.GetTagName(), err.Error()})
+			}
+			continue
+		}
+		bases = append(bases, Base{r, v})
+	}
+	return bases, skipped
+}
@@ -0,0 +1,51 @@
+package plan
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v29/github"
+)
+
+func TestBases(t *testing.T) {
+	day := func(d int) *github.Timestamp {
+		return &github.Timestamp{Time: time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC)}
+	}
+	releases := []*github.RepositoryRelease{
+		{TagName: github.String("v1.1"), PublishedAt: day(3)},
+		{TagName: github.String("nightly"), PublishedAt: day(9)},
+		{TagName: github.String("v1.0.0"), PublishedAt: day(1)},
+		{TagName: github.String("release-final"), PublishedAt: day(8)},
+		{TagName: github.String("v2.0.0-rc.1"), PublishedAt: day(7), Prerelease: github.Bool(true)},
+		{TagName: github.String("v9.9.9"), PublishedAt: day(10)}, // newer than latest
+		{TagName: github.String("old-junk"), PublishedAt: day(2)},
+	}
+
+	testCases := []struct {
+		mode        TagMode
+		wantBases   []string
+		wantSkipped []string
+	}{
+		{Lenient, []string{"v1.1", "v1.0.0"}, []string{"nightly", "release-final"}},
+		{Strict, []string{"v1.0.0"}, []string{"nightly", "release-final", "v1.1", "old-junk"}},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			bases, skipped := Bases(releases, "nightly", tc.mode)
+			var gotBases, gotSkipped []string
+			for _, b := range bases {
+				gotBases = append(gotBases, b.Release.GetTagName())
+			}
+			for _, s := range skipped {
+				gotSkipped = append(gotSkipped, s.Tag)
+			}
+			if !slices.Equal(gotBases, tc.wantBases) {
+				t.Errorf("bases = %v, want %v", gotBases, tc.wantBases)
+			}
+			if !slices.Equal(gotSkipped, tc.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", gotSkipped, tc.wantSkipped)
+			}
+		})
+	}
+}
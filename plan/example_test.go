@@ -0,0 +1,27 @@
+package plan_test
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/mroth/bump/plan"
+)
+
+func ExampleChoices() {
+	for _, c := range plan.Choices(semver.MustParse("1.4.2")) {
+		fmt.Printf("%s: %s\n", c.Name, c.Version.String())
+	}
+	// Output:
+	// patch: 1.4.3
+	// minor: 1.5.0
+	// major: 2.0.0
+}
+
+func ExampleParseLine() {
+	line, ok := plan.ParseLine("release/1.x")
+	fmt.Println(line, ok)
+	fmt.Println(line.Contains(semver.MustParse("1.8.2")), line.Contains(semver.MustParse("2.4.0")))
+	// Output:
+	// 1.x true
+	// true false
+}
@@ -0,0 +1,103 @@
+package plan
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/v29/github"
+)
+
+// Line identifies a maintenance line of releases, such as 1.x or 1.8.x,
+// typically released from a dedicated branch.
+type Line struct {
+	Major    uint64
+	Minor    uint64
+	HasMinor bool // line is restricted to a single major.minor
+}
+
+// shaPattern matches full or abbreviated commit SHAs.
+var shaPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// IsSHA reports whether ref looks like a commit SHA, full or abbreviated,
+// rather than the name of a branch.
+func IsSHA(ref string) bool {
+	return shaPattern.MatchString(ref)
+}
+
+// releaseLinePattern matches branch names for maintenance lines, e.g.
+// release/1.x, release-1.8, maint/2.x, v2, 1.8.x, capturing the prefix before
+// the version.
+var releaseLinePattern = regexp.MustCompile(`^((?:release|maint|maintenance)[/-]v?|v)?(\d+)(?:\.(\d+))?(\.x)?$`)
+
+// ParseLine infers the release line from the name of a maintenance branch,
+// such as release/1.x, with ok reporting whether the branch looked like one.
+//
+// Only names which are explicitly about a line count: those with a release or
+// maint prefix, a v prefix, or an .x suffix. Names which merely end in a
+// number, such as fix-123 or hotfix/2, do not.
+func ParseLine(branch string) (line Line, ok bool) {
+	if IsSHA(branch) {
+		return line, false
+	}
+	m := releaseLinePattern.FindStringSubmatch(branch)
+	if m == nil || m[1] == "" && m[4] == "" {
+		return line, false
+	}
+	line.Major, _ = strconv.ParseUint(m[2], 10, 64)
+	if m[3] != "" {
+		line.Minor, _ = strconv.ParseUint(m[3], 10, 64)
+		line.HasMinor = true
+	}
+	return line, true
+}
+
+// Contains reports whether v is part of the release line.
+func (l Line) Contains(v *semver.Version) bool {
+	if v.Major() != l.Major {
+		return false
+	}
+	return !l.HasMinor || v.Minor() == l.Minor
+}
+
+func (l Line) String() string {
+	if l.HasMinor {
+		return fmt.Sprintf("%d.%d.x", l.Major, l.Minor)
+	}
+	return fmt.Sprintf("%d.x", l.Major)
+}
+
+// LatestInLine returns the published release with the highest semantic
+// version in line, parsing tags with mode. Like the GitHub "latest" release,
+// drafts and prereleases are not considered.
+func LatestInLine(releases []*github.RepositoryRelease, line Line, mode TagMode) (*github.RepositoryRelease, error) {
+	latest := Highest(releases, mode, line.Contains)
+	if latest == nil {
+		return nil, fmt.Errorf("no releases found in release line %v", line)
+	}
+	return latest, nil
+}
+
+// Highest returns the published release with the highest semantic version
+// for which include returns true, or nil if there is none. Drafts, prereleases
+// and tags which are not semantic versions when parsed with mode are skipped.
+func Highest(releases []*github.RepositoryRelease, mode TagMode, include func(*semver.Version) bool) *github.RepositoryRelease {
+	var (
+		latest        *github.RepositoryRelease
+		latestVersion *semver.Version
+	)
+	for _, r := range releases {
+		if r.GetDraft() || r.GetPrerelease() {
+			continue
+		}
+		v, err := mode.Parse(r.GetTagName())
+		if err != nil || v.Prerelease() != "" || !include(v) {
+			continue
+		}
+		if latestVersion == nil || v.GreaterThan(latestVersion) {
+			latest, latestVersion = r, v
+		}
+	}
+	return latest
+}
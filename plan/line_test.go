@@ -0,0 +1,114 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/v29/github"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   string
+		wantOk bool
+	}{
+		{"release/1.x", "1.x", true},
+		{"release-1.x", "1.x", true},
+		{"release/1.8", "1.8.x", true},
+		{"1.8.x", "1.8.x", true},
+		{"v2", "2.x", true},
+		{"maint/2.x", "2.x", true},
+		{"release/v1.8", "1.8.x", true},
+		{"main", "", false},
+		{"feature/oauth2-login", "", false},
+		{"", "", false},
+		{"0123abc", "", false}, // short sha, not a branch
+		{"fix-123", "", false},
+		{"feature/issue-42", "", false},
+		{"hotfix/2", "", false},
+		{"dependabot/npm/foo-1.2", "", false},
+		{"1.8", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			line, ok := ParseLine(tt.branch)
+			if ok != tt.wantOk {
+				t.Fatalf("ParseLine(%q) ok = %v, want %v", tt.branch, ok, tt.wantOk)
+			}
+			if ok && line.String() != tt.want {
+				t.Errorf("ParseLine(%q) = %v, want %v", tt.branch, line, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSHA(t *testing.T) {
+	for ref, want := range map[string]bool{
+		"0123abc": true,
+		"a1b2c3d4e5f6789012345678901234567890abcd": true,
+		"012345":      false, // too short
+		"main":        false,
+		"release/1.x": false,
+		"0123ABC":     false,
+	} {
+		if got := IsSHA(ref); got != want {
+			t.Errorf("IsSHA(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
+
+func TestLatestInLine(t *testing.T) {
+	releases := []*github.RepositoryRelease{
+		{TagName: github.String("v2.4.0")},
+		{TagName: github.String("v1.8.2")},
+		{TagName: github.String("v1.10.0")},
+		{TagName: github.String("v1.8.10")},
+		{TagName: github.String("v1.9.0-rc.1")},
+		{TagName: github.String("v1.11.0"), Draft: github.Bool(true)},
+		{TagName: github.String("v1.12.0"), Prerelease: github.Bool(true)},
+		{TagName: github.String("nightly")},
+	}
+	tests := []struct {
+		line Line
+		want string
+	}{
+		{Line{Major: 1}, "v1.10.0"},
+		{Line{Major: 1, Minor: 8, HasMinor: true}, "v1.8.10"},
+		{Line{Major: 2}, "v2.4.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line.String(), func(t *testing.T) {
+			got, err := LatestInLine(releases, tt.line, Lenient)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.GetTagName() != tt.want {
+				t.Errorf("LatestInLine() = %v, want %v", got.GetTagName(), tt.want)
+			}
+		})
+	}
+
+	if _, err := LatestInLine(releases, Line{Major: 3}, Lenient); err == nil {
+		t.Error("want error for line with no releases")
+	}
+
+	// v1.11 only counts as 1.11.0 when leniently parsed
+	lenient := append(releases, &github.RepositoryRelease{TagName: github.String("v1.11")})
+	if got, _ := LatestInLine(lenient, Line{Major: 1}, Lenient); got.GetTagName() != "v1.11" {
+		t.Errorf("LatestInLine() lenient = %v, want v1.11", got.GetTagName())
+	}
+	if got, _ := LatestInLine(lenient, Line{Major: 1}, Strict); got.GetTagName() != "v1.10.0" {
+		t.Errorf("LatestInLine() strict = %v, want v1.10.0", got.GetTagName())
+	}
+}
+
+func TestLineContains(t *testing.T) {
+	line := Line{Major: 1, Minor: 8, HasMinor: true}
+	if !line.Contains(semver.MustParse("1.8.3")) {
+		t.Error("1.8.x should contain 1.8.3")
+	}
+	if line.Contains(semver.MustParse("1.9.0")) {
+		t.Error("1.8.x should not contain 1.9.0")
+	}
+}
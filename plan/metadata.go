@@ -0,0 +1,49 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// MetadataInfo is the data available to build metadata templates, such as
+// "sha.{{.ShortSHA}}" or "build.{{.Date}}".
+type MetadataInfo struct {
+	SHA      string // full SHA of the commit being released
+	ShortSHA string // abbreviated SHA, 7 characters
+	Date     string // date of the release, as YYYYMMDD
+}
+
+// NewMetadataInfo returns the MetadataInfo for releasing commit sha at t.
+func NewMetadataInfo(sha string, t time.Time) MetadataInfo {
+	return MetadataInfo{
+		SHA:      sha,
+		ShortSHA: sha[:min(7, len(sha))],
+		Date:     t.Format("20060102"),
+	}
+}
+
+// WithMetadata returns v with build metadata rendered from the text/template
+// tmpl, e.g. "1.4.2" with "sha.{{.ShortSHA}}" becomes "1.4.2+sha.abc1234".
+// The result must be valid semver build metadata: dot separated identifiers
+// of ASCII alphanumerics and hyphens.
+func WithMetadata(v *semver.Version, tmpl string, info MetadataInfo) (*semver.Version, error) {
+	t, err := template.New("metadata").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata template: %w", err)
+	}
+	var buf strings.Builder
+	if err := t.Execute(&buf, info); err != nil {
+		return nil, fmt.Errorf("invalid metadata template: %w", err)
+	}
+	metadata := strings.TrimPrefix(buf.String(), "+")
+	if _, err := v.SetMetadata(metadata); err != nil {
+		return nil, fmt.Errorf("invalid build metadata %q: %w", metadata, err)
+	}
+	// parsed rather than using SetMetadata's result, to keep CalVer padding
+	base, _, _ := strings.Cut(VersionString(v), "+")
+	return semver.NewVersion(base + "+" + metadata)
+}
@@ -0,0 +1,56 @@
+// Package plan works out the versions involved in a release: which release
+// is the previous one, and what the candidates for the next version are.
+package plan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Choice is a candidate for the next version.
+type Choice struct {
+	Name        string // short name, such as "patch"
+	Version     semver.Version
+	Description string // when to choose this option
+}
+
+// Choices returns the possible semver increments following current, from
+// least to most significant.
+func Choices(current *semver.Version) []Choice {
+	return []Choice{
+		{"patch", current.IncPatch(), "when you make backwards-compatible bug fixes."},
+		{"minor", current.IncMinor(), "when you add functionality in a backwards-compatible manner."},
+		{"major", current.IncMajor(), "when you make incompatible API changes."},
+	}
+}
+
+// fullVersionPattern matches versions with all three segments, so that
+// ParseCustom does not accept shorthand like "3".
+var fullVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(?:[-+].*)?$`)
+
+// ParseCustom parses a version entered by the user to follow current, which
+// must be a complete semver (optionally prefixed with "v") greater than
+// current, and must not already be tagged as one of tags. Zero padded
+// segments are allowed, for CalVer versions such as 26.01.3.
+func ParseCustom(input string, current *semver.Version, tags []string) (*semver.Version, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(input), "v")
+	if !fullVersionPattern.MatchString(s) {
+		return nil, fmt.Errorf("%q is not a semantic version", input)
+	}
+	v, err := semver.NewVersion(s)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a semantic version", input)
+	}
+	if !v.GreaterThan(current) {
+		return nil, fmt.Errorf("%v is not greater than the previous version %v", VersionString(v), VersionString(current))
+	}
+	for _, tag := range tags {
+		if t, err := semver.NewVersion(tag); err == nil && t.Equal(v) && t.Metadata() == v.Metadata() {
+			return nil, fmt.Errorf("%v is already tagged as %s", VersionString(v), tag)
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,73 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestParseCustom(t *testing.T) {
+	current := semver.MustParse("2.4.1")
+	tags := []string{"v2.4.1", "v3.0.0-rc.1", "not-a-version"}
+
+	testCases := []struct {
+		input string
+		want  string // empty if error expected
+	}{
+		{"3.0.0", "3.0.0"},
+		{" v3.0.0 ", "3.0.0"},
+		{"2.5.0-beta.1", "2.5.0-beta.1"},
+		{"3", ""},          // incomplete
+		{"banana", ""},     // not semver
+		{"2.4.1", ""},      // not greater
+		{"1.9.0", ""},      // not greater
+		{"3.0.0-rc.1", ""}, // already tagged
+	}
+	for _, tc := range testCases {
+		got, err := ParseCustom(tc.input, current, tags)
+		if tc.want == "" {
+			if err == nil {
+				t.Errorf("ParseCustom(%q) = %v, want error", tc.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseCustom(%q) unexpected error: %v", tc.input, err)
+		} else if got.String() != tc.want {
+			t.Errorf("ParseCustom(%q) = %v, want %v", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestWithMetadata(t *testing.T) {
+	v := semver.MustParse("1.5.0")
+	info := NewMetadataInfo("abc1234def5678", time.Date(2025, 7, 22, 0, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		tmpl string
+		want string // empty if error expected
+	}{
+		{"build.42", "1.5.0+build.42"},
+		{"+build.42", "1.5.0+build.42"},
+		{"sha.{{.ShortSHA}}", "1.5.0+sha.abc1234"},
+		{"{{.Date}}.{{.SHA}}", "1.5.0+20250722.abc1234def5678"},
+		{"build_42", ""},    // invalid character
+		{"{{.Nope}}", ""},   // unknown field
+		{"{{.ShortSHA", ""}, // bad template
+	}
+	for _, tc := range testCases {
+		got, err := WithMetadata(v, tc.tmpl, info)
+		if tc.want == "" {
+			if err == nil {
+				t.Errorf("WithMetadata(%q) = %v, want error", tc.tmpl, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("WithMetadata(%q) unexpected error: %v", tc.tmpl, err)
+		} else if got.String() != tc.want {
+			t.Errorf("WithMetadata(%q) = %v, want %v", tc.tmpl, got, tc.want)
+		}
+	}
+}
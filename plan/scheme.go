@@ -0,0 +1,113 @@
+package plan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Scheme is a versioning scheme, which decides the candidates for the version
+// following current when releasing at time now.
+type Scheme interface {
+	Choices(current *semver.Version, now time.Time) []Choice
+}
+
+// SemVer is the default Scheme, offering the patch, minor and major
+// increments of the current version.
+var SemVer Scheme = semverScheme{}
+
+type semverScheme struct{}
+
+func (semverScheme) Choices(current *semver.Version, _ time.Time) []Choice {
+	return Choices(current)
+}
+
+// ParseScheme returns the Scheme named by s, which is either "semver" (the
+// default, also used if s is empty) or a CalVer format such as "YYYY.MM.MICRO".
+func ParseScheme(s string) (Scheme, error) {
+	if s == "" || strings.EqualFold(s, "semver") {
+		return SemVer, nil
+	}
+	return NewCalVer(s)
+}
+
+// CalVer is a calendar versioning Scheme (see https://calver.org), where the
+// first two segments of the version are taken from the release date and the
+// third is a MICRO number counting releases within that period.
+type CalVer struct {
+	format [2]string // date tokens of the first two segments
+}
+
+// calverTokens format each supported date token of a CalVer format.
+var calverTokens = map[string]func(t time.Time) string{
+	"YYYY": func(t time.Time) string { return strconv.Itoa(t.Year()) },
+	"YY":   func(t time.Time) string { return strconv.Itoa(t.Year() - 2000) },
+	"0Y":   func(t time.Time) string { return fmt.Sprintf("%02d", t.Year()-2000) },
+	"MM":   func(t time.Time) string { return strconv.Itoa(int(t.Month())) },
+	"0M":   func(t time.Time) string { return fmt.Sprintf("%02d", int(t.Month())) },
+	"WW":   func(t time.Time) string { _, w := t.ISOWeek(); return strconv.Itoa(w) },
+	"0W":   func(t time.Time) string { _, w := t.ISOWeek(); return fmt.Sprintf("%02d", w) },
+	"DD":   func(t time.Time) string { return strconv.Itoa(t.Day()) },
+	"0D":   func(t time.Time) string { return fmt.Sprintf("%02d", t.Day()) },
+}
+
+// NewCalVer returns the CalVer Scheme for format, which must be a year token
+// (YYYY, YY or 0Y), then a month, week or day token (MM, 0M, WW, 0W, DD or
+// 0D), then MICRO, separated by dots: e.g. "YYYY.MM.MICRO" or "YY.0M.MICRO".
+func NewCalVer(format string) (CalVer, error) {
+	segments := strings.Split(format, ".")
+	valid := len(segments) == 3 && segments[2] == "MICRO"
+	if valid {
+		switch segments[0] {
+		case "YYYY", "YY", "0Y":
+		default:
+			valid = false
+		}
+		if _, ok := calverTokens[segments[1]]; !ok || strings.Contains(segments[1], "Y") {
+			valid = false
+		}
+	}
+	if !valid {
+		return CalVer{}, fmt.Errorf("unknown versioning scheme %q (want semver, or a CalVer format like YYYY.MM.MICRO)", format)
+	}
+	return CalVer{format: [2]string{segments[0], segments[1]}}, nil
+}
+
+// String returns the format of the scheme.
+func (c CalVer) String() string {
+	return c.format[0] + "." + c.format[1] + ".MICRO"
+}
+
+// Choices implements Scheme. There is a single choice: the next MICRO release
+// in the period of current if now is still in it, otherwise the first release
+// of the period of now.
+func (c CalVer) Choices(current *semver.Version, now time.Time) []Choice {
+	year, period := calverTokens[c.format[0]](now), calverTokens[c.format[1]](now)
+
+	// compare numerically, as the current version may not have been padded
+	y, _ := strconv.ParseUint(year, 10, 64)
+	p, _ := strconv.ParseUint(period, 10, 64)
+	if current.Major() == y && current.Minor() == p {
+		v := calverVersion(year, period, current.Patch()+1)
+		return []Choice{{"micro", v, fmt.Sprintf("another release in %s.%s.", year, period)}}
+	}
+	v := calverVersion(year, period, 0)
+	return []Choice{{"calendar", v, fmt.Sprintf("the first release in %s.%s.", year, period)}}
+}
+
+func calverVersion(year, period string, micro uint64) semver.Version {
+	// parsed rather than semver.New, so that VersionString keeps any padding
+	return *semver.MustParse(fmt.Sprintf("%s.%s.%d", year, period, micro))
+}
+
+// VersionString returns v as it was written, without any "v" prefix. Unlike
+// v.String, this keeps the zero padding of CalVer versions like 26.01.3.
+func VersionString(v *semver.Version) string {
+	if s := strings.TrimPrefix(v.Original(), "v"); s != "" {
+		return s
+	}
+	return v.String()
+}
@@ -0,0 +1,51 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestCalVer(t *testing.T) {
+	now := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		format  string
+		current string
+		want    string
+	}{
+		{"YYYY.MM.MICRO", "v2025.12.4", "2026.1.0"},
+		{"YYYY.MM.MICRO", "v2026.1.4", "2026.1.5"},
+		{"YYYY.0M.MICRO", "v2026.01.4", "2026.01.5"},
+		{"YY.0M.MICRO", "v25.12.0", "26.01.0"},
+		{"YY.MM.MICRO", "26.1.2", "26.1.3"},
+		{"0Y.0W.MICRO", "v26.01.0", "26.02.0"}, // 2026-01-05 is in ISO week 2
+		{"YYYY.0D.MICRO", "v2026.05.1", "2026.05.2"},
+	}
+	for _, tc := range testCases {
+		scheme, err := ParseScheme(tc.format)
+		if err != nil {
+			t.Fatalf("ParseScheme(%q): %v", tc.format, err)
+		}
+		choices := scheme.Choices(semver.MustParse(tc.current), now)
+		if len(choices) != 1 {
+			t.Fatalf("%s: want a single choice, got %+v", tc.format, choices)
+		}
+		if got := VersionString(&choices[0].Version); got != tc.want {
+			t.Errorf("%s after %s = %v, want %v", tc.format, tc.current, got, tc.want)
+		}
+	}
+}
+
+func TestParseScheme(t *testing.T) {
+	for _, ok := range []string{"", "semver", "SemVer", "YYYY.MM.MICRO", "YY.0M.MICRO", "0Y.WW.MICRO"} {
+		if _, err := ParseScheme(ok); err != nil {
+			t.Errorf("ParseScheme(%q) unexpected error: %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"calver", "YYYY.MM", "YYYY.MM.DD", "MM.YYYY.MICRO", "YYYY.YY.MICRO", "YYYY.MM.MICRO.MICRO"} {
+		if _, err := ParseScheme(bad); err == nil {
+			t.Errorf("ParseScheme(%q) want error, got nil", bad)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package plan
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v29/github"
+)
+
+// conventionalPattern matches the header of a Conventional Commits message,
+// capturing its type and the "!" marking a breaking change.
+var conventionalPattern = regexp.MustCompile(`^(\w+)(?:\([^)]*\))?(!)?: `)
+
+// ParseConventional parses the type of a Conventional Commits message
+// (https://conventionalcommits.org), such as "feat" for "feat(cli): add
+// --foo", and whether the commit is marked as a breaking change, either by a
+// "!" after the type or a BREAKING CHANGE footer. ok is false if the message
+// is not a conventional commit, though a BREAKING CHANGE footer still counts.
+func ParseConventional(msg string) (typ string, breaking, ok bool) {
+	breaking = strings.Contains(msg, "\nBREAKING CHANGE: ") || strings.Contains(msg, "\nBREAKING-CHANGE: ")
+	m := conventionalPattern.FindStringSubmatch(msg)
+	if m == nil {
+		return "", breaking, false
+	}
+	return strings.ToLower(m[1]), breaking || m[2] == "!", true
+}
+
+// Increment returns the semver increment called for by the commits in
+// comparison, following Conventional Commits: "major" if any commit is a
+// breaking change, "minor" if any adds a feature, and "patch" otherwise.
+func Increment(comparison *github.CommitsComparison) string {
+	increment := "patch"
+	for _, c := range comparison.Commits {
+		typ, breaking, _ := ParseConventional(c.GetCommit().GetMessage())
+		switch {
+		case breaking:
+			return "major"
+		case typ == "feat":
+			increment = "minor"
+		}
+	}
+	return increment
+}
+
+// Suggest picks the choice named by the Increment called for by the commits
+// in comparison, or the first choice if there is none by that name, as for
+// schemes other than SemVer.
+func Suggest(choices []Choice, comparison *github.CommitsComparison) Choice {
+	increment := Increment(comparison)
+	if i := slices.IndexFunc(choices, func(c Choice) bool { return c.Name == increment }); i != -1 {
+		return choices[i]
+	}
+	return choices[0]
+}
@@ -0,0 +1,76 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/v29/github"
+)
+
+func comparisonOf(messages ...string) *github.CommitsComparison {
+	var cc github.CommitsComparison
+	for _, msg := range messages {
+		cc.Commits = append(cc.Commits, github.RepositoryCommit{
+			Commit: &github.Commit{Message: github.String(msg)},
+		})
+	}
+	return &cc
+}
+
+func TestParseConventional(t *testing.T) {
+	tests := []struct {
+		msg          string
+		wantType     string
+		wantBreaking bool
+		wantOk       bool
+	}{
+		{"feat(cli): add --foo", "feat", false, true},
+		{"Fix!: drop v1", "fix", true, true},
+		{"fix: parse dates\n\nBREAKING CHANGE: returns UTC", "fix", true, true},
+		{"Parse dates\n\nBREAKING CHANGE: returns UTC", "", true, false},
+		{"add a feature", "", false, false},
+	}
+	for _, tt := range tests {
+		typ, breaking, ok := ParseConventional(tt.msg)
+		if typ != tt.wantType || breaking != tt.wantBreaking || ok != tt.wantOk {
+			t.Errorf("ParseConventional(%q) = %q, %v, %v, want %q, %v, %v",
+				tt.msg, typ, breaking, ok, tt.wantType, tt.wantBreaking, tt.wantOk)
+		}
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		want     string
+	}{
+		{"none", nil, "patch"},
+		{"fixes", []string{"fix: off by one", "docs: typo"}, "patch"},
+		{"not conventional", []string{"add a feature", "feature: nope"}, "patch"},
+		{"feature", []string{"fix: off by one", "feat(cli): add --foo"}, "minor"},
+		{"breaking bang", []string{"feat: add --foo", "refactor(api)!: drop v1"}, "major"},
+		{"breaking footer", []string{"fix: parse dates\n\nBREAKING CHANGE: returns UTC"}, "major"},
+		{"breaking footer, not conventional", []string{"Parse dates\n\nBREAKING-CHANGE: returns UTC"}, "major"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Increment(comparisonOf(tt.messages...)); got != tt.want {
+				t.Errorf("Increment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	cc := comparisonOf("feat: add --foo")
+	if got := Suggest(Choices(semver.MustParse("1.4.2")), cc); got.Version.String() != "1.5.0" {
+		t.Errorf("Suggest(semver) = %v, want 1.5.0", got.Version)
+	}
+	calver, _ := NewCalVer("YYYY.MM.MICRO")
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if got := Suggest(calver.Choices(semver.MustParse("2026.3.1"), now), cc); got.Name != "micro" {
+		t.Errorf("Suggest(calver) = %v, want micro", got.Name)
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mroth/bump/release"
+)
+
+// CheckLevel controls what happens when a preflight check finds a problem.
+type CheckLevel string
+
+// Possible CheckLevel values.
+const (
+	CheckOff   CheckLevel = "off"   // check is not run at all
+	CheckWarn  CheckLevel = "warn"  // problem is reported, release continues
+	CheckBlock CheckLevel = "block" // problem is reported, release is aborted
+)
+
+func (l CheckLevel) valid() bool {
+	switch l {
+	case CheckOff, CheckWarn, CheckBlock:
+		return true
+	default:
+		return false
+	}
+}
+
+// preflightCheck is a single sanity check of the local working copy, run
+// before drafting a release. Run returns a human readable description of the
+// problem found, or an empty string if everything looks fine.
+type preflightCheck struct {
+	Name    string
+	Default CheckLevel
+	Run     func(r *git.Repository, rs release.RemoteState) (string, error)
+}
+
+var preflightChecks = []preflightCheck{
+	{"dirty", CheckWarn, checkDirtyWorktree},
+	{"unpushed", CheckBlock, checkUnpushedCommits},
+	{"branch", CheckWarn, checkDefaultBranch},
+	{"head", CheckWarn, checkRemoteHead},
+}
+
+func findPreflightCheck(name string) *preflightCheck {
+	for i := range preflightChecks {
+		if preflightChecks[i].Name == name {
+			return &preflightChecks[i]
+		}
+	}
+	return nil
+}
+
+// preflightResult records a problem found by a preflight check.
+type preflightResult struct {
+	Check   string
+	Level   CheckLevel
+	Problem string
+}
+
+// runPreflightChecks runs all preflight checks against the local repository,
+// using levels to override their default CheckLevel, and returns the problems
+// found.
+func runPreflightChecks(r *git.Repository, rs release.RemoteState, levels map[string]CheckLevel) ([]preflightResult, error) {
+	var results []preflightResult
+	for _, c := range preflightChecks {
+		level := c.Default
+		if l, ok := levels[c.Name]; ok {
+			level = l
+		}
+		if level == CheckOff {
+			continue
+		}
+
+		problem, err := c.Run(r, rs)
+		if err != nil {
+			return nil, fmt.Errorf("preflight check %s: %w", c.Name, err)
+		}
+		if problem != "" {
+			results = append(results, preflightResult{c.Name, level, problem})
+		}
+	}
+	return results, nil
+}
+
+// checkDirtyWorktree reports modified or staged files in the working tree.
+// Untracked files are ignored, matching the behavior of git describe --dirty.
+func checkDirtyWorktree(r *git.Repository, _ release.RemoteState) (string, error) {
+	wt, err := r.Worktree()
+	if errors.Is(err, git.ErrIsBareRepository) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return "", err
+	}
+
+	var changed int
+	for _, fs := range status {
+		if fs.Worktree == git.Untracked {
+			continue
+		}
+		if fs.Staging != git.Unmodified || fs.Worktree != git.Unmodified {
+			changed++
+		}
+	}
+	if changed > 0 {
+		return fmt.Sprintf("working tree has uncommitted changes to %d file(s)", changed), nil
+	}
+	return "", nil
+}
+
+// checkUnpushedCommits reports local commits on the current branch which are
+// not present on its upstream branch.
+func checkUnpushedCommits(r *git.Repository, rs release.RemoteState) (string, error) {
+	head, err := r.Head()
+	if err != nil {
+		return "", err
+	}
+	if !head.Name().IsBranch() {
+		// detached HEAD has no upstream, check against the target branch
+		upstream := plumbing.NewRemoteReferenceName(rs.RemoteName, cmp.Or(rs.TargetBranch(), rs.DefaultBranch))
+		return unpushedAgainst(r, head, upstream, "HEAD")
+	}
+
+	branch := head.Name().Short()
+	upstream := plumbing.NewRemoteReferenceName(rs.RemoteName, branch)
+	if bc, err := r.Branch(branch); err == nil && bc.Remote != "" && bc.Merge.IsBranch() {
+		upstream = plumbing.NewRemoteReferenceName(bc.Remote, bc.Merge.Short())
+	}
+	return unpushedAgainst(r, head, upstream, "branch "+branch)
+}
+
+func unpushedAgainst(r *git.Repository, head *plumbing.Reference, upstream plumbing.ReferenceName, desc string) (string, error) {
+	remoteRef, err := r.Reference(upstream, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return fmt.Sprintf("%s has no upstream %s, it may not be pushed", desc, upstream.Short()), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if remoteRef.Hash() == head.Hash() {
+		return "", nil
+	}
+
+	ahead, err := countCommitsAhead(r, head.Hash(), remoteRef.Hash())
+	if err != nil {
+		return "", err
+	}
+	if ahead > 0 {
+		return fmt.Sprintf("%s has %d commit(s) not pushed to %s", desc, ahead, upstream.Short()), nil
+	}
+	return "", nil
+}
+
+// countCommitsAhead returns the number of commits reachable from local which
+// are not reachable from remote, similar to git rev-list --count remote..local.
+func countCommitsAhead(r *git.Repository, local, remote plumbing.Hash) (int, error) {
+	localCommit, err := r.CommitObject(local)
+	if err != nil {
+		return 0, err
+	}
+	remoteCommit, err := r.CommitObject(remote)
+	if err != nil {
+		return 0, err
+	}
+	bases, err := localCommit.MergeBase(remoteCommit)
+	if err != nil {
+		return 0, err
+	}
+	ignore := make([]plumbing.Hash, len(bases))
+	for i, b := range bases {
+		ignore[i] = b.Hash
+	}
+
+	var count int
+	err = object.NewCommitPreorderIter(localCommit, nil, ignore).ForEach(func(*object.Commit) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// checkDefaultBranch reports when the current branch, or the branch on GitHub
+// that it tracks, is not the branch the release is drafted from, or when that
+// is not the default branch on GitHub.
+func checkDefaultBranch(r *git.Repository, rs release.RemoteState) (string, error) {
+	target := rs.TargetBranch()
+	if target == "" {
+		return "", nil // releasing a specific commit, head check covers it
+	}
+	head, err := r.Head()
+	if err != nil {
+		return "", err
+	}
+	if !head.Name().IsBranch() {
+		return fmt.Sprintf("HEAD is detached, not on release branch %s", target), nil
+	}
+	branch := head.Name().Short()
+	upstream := branch
+	if bc, err := r.Branch(branch); err == nil && bc.Remote == rs.RemoteName && bc.Merge.IsBranch() {
+		upstream = bc.Merge.Short() // tracked under another name, e.g. "main" for "stable"
+	}
+	if upstream != target {
+		return fmt.Sprintf("on branch %s, not release branch %s", branch, target), nil
+	}
+	if target != rs.DefaultBranch {
+		return fmt.Sprintf("releasing from branch %s, not default branch %s", target, rs.DefaultBranch), nil
+	}
+	return "", nil
+}
+
+// checkRemoteHead reports when the local HEAD is not the same commit that
+// GitHub will use as HEAD when comparing against the previous release.
+func checkRemoteHead(r *git.Repository, rs release.RemoteState) (string, error) {
+	head, err := r.Head()
+	if err != nil {
+		return "", err
+	}
+	if local := head.Hash().String(); local != rs.HeadSHA {
+		return fmt.Sprintf("local HEAD %.7s differs from %s %.7s on GitHub used for the changelog",
+			local, cmp.Or(rs.Target, rs.DefaultBranch), rs.HeadSHA), nil
+	}
+	return "", nil
+}
+
+// errPreflightBlocked is returned by preflight when a check at CheckBlock
+// level found a problem.
+var errPreflightBlocked = errors.New("preflight checks failed, fix the problems above or rerun with --skip-checks")
+
+// preflight runs the preflight checks on the local repository against the
+// state of the repository on GitHub, reporting any problems found to w.
+func preflight(w io.Writer, gitRepo *git.Repository, rs release.RemoteState, cfg *Config) error {
+	defer timeTrack(time.Now(), "preflight()")
+	results, err := runPreflightChecks(gitRepo, rs, cfg.Checks)
+	if err != nil {
+		return err
+	}
+	var blocked bool
+	for _, res := range results {
+		icon := "⚠️ "
+		if res.Level == CheckBlock {
+			icon, blocked = "🛑", true
+		}
+		fmt.Fprintf(w, "%s %s %s\n", icon, res.Problem, faintStyler("("+res.Check+")"))
+	}
+	if blocked {
+		return errPreflightBlocked
+	}
+	return nil
+}
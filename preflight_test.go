@@ -0,0 +1,214 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/mroth/bump/internal/bumptest"
+	"github.com/mroth/bump/release"
+	"github.com/mroth/bump/remote"
+)
+
+func TestPreflightChecks(t *testing.T) {
+	tr := bumptest.NewRepo(t)
+	pushed := tr.Commit("a.txt", "a", "initial")
+	tr.SetRef(originMaster, pushed)
+	rs := release.RemoteState{RemoteName: "origin", DefaultBranch: "master", HeadSHA: pushed.String()}
+
+	t.Run("clean", func(t *testing.T) {
+		results, err := runPreflightChecks(tr.Repository, rs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 0 {
+			t.Errorf("want no problems, got %+v", results)
+		}
+	})
+
+	t.Run("dirty", func(t *testing.T) {
+		tr.Write("a.txt", "modified")
+		tr.Write("untracked.txt", "ignored by check")
+		defer tr.Write("a.txt", "a")
+
+		results, err := runPreflightChecks(tr.Repository, rs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertChecks(t, results, map[string]CheckLevel{"dirty": CheckWarn})
+	})
+
+	t.Run("unpushed", func(t *testing.T) {
+		tr.Commit("b.txt", "b", "second")
+		tr.Commit("c.txt", "c", "third")
+		defer tr.Reset(pushed)
+
+		results, err := runPreflightChecks(tr.Repository, rs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertChecks(t, results, map[string]CheckLevel{"unpushed": CheckBlock, "head": CheckWarn})
+		if got, want := results[0].Problem, "branch master has 2 commit(s) not pushed to origin/master"; got != want {
+			t.Errorf("problem = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("branch", func(t *testing.T) {
+		tr.Checkout("feature", true)
+		tr.SetRef(plumbing.NewRemoteReferenceName("origin", "feature"), pushed)
+		defer tr.Checkout("master", false)
+
+		results, err := runPreflightChecks(tr.Repository, rs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertChecks(t, results, map[string]CheckLevel{"branch": CheckWarn})
+	})
+
+	t.Run("tracking branch of another name", func(t *testing.T) {
+		tr.Checkout("local-master", true)
+		defer tr.Checkout("master", false)
+		track := func(remoteName, merge string) {
+			t.Helper()
+			cfg, err := tr.Config()
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.Branches["local-master"] = &config.Branch{
+				Name:   "local-master",
+				Remote: remoteName,
+				Merge:  plumbing.NewBranchReferenceName(merge),
+			}
+			if err := tr.SetConfig(cfg); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		track("origin", "master")
+		results, err := runPreflightChecks(tr.Repository, rs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertChecks(t, results, nil)
+
+		track("origin", "feature")
+		results, err = runPreflightChecks(tr.Repository, rs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertChecks(t, results, map[string]CheckLevel{"branch": CheckWarn})
+		if got, want := results[0].Problem, "on branch local-master, not release branch master"; got != want {
+			t.Errorf("problem = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("maintenance branch target", func(t *testing.T) {
+		tr.Checkout("release/1.x", true)
+		tr.SetRef(plumbing.NewRemoteReferenceName("origin", "release/1.x"), pushed)
+		defer tr.Checkout("master", false)
+
+		target := rs
+		target.Target = "release/1.x"
+		results, err := runPreflightChecks(tr.Repository, target, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertChecks(t, results, map[string]CheckLevel{"branch": CheckWarn})
+		if got, want := results[0].Problem, "releasing from branch release/1.x, not default branch master"; got != want {
+			t.Errorf("problem = %q, want %q", got, want)
+		}
+
+		target.Target = pushed.String()[:7]
+		results, err = runPreflightChecks(tr.Repository, target, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertChecks(t, results, nil)
+	})
+
+	t.Run("levels override defaults", func(t *testing.T) {
+		tr.Commit("d.txt", "d", "fourth")
+		defer tr.Reset(pushed)
+
+		levels := map[string]CheckLevel{"unpushed": CheckOff, "head": CheckBlock}
+		results, err := runPreflightChecks(tr.Repository, rs, levels)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertChecks(t, results, map[string]CheckLevel{"head": CheckBlock})
+	})
+}
+
+func assertChecks(t *testing.T, results []preflightResult, want map[string]CheckLevel) {
+	t.Helper()
+	got := make(map[string]CheckLevel)
+	for _, r := range results {
+		got[r.Check] = r.Level
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got problems %+v, want checks %v", results, want)
+	}
+	for name, level := range want {
+		if got[name] != level {
+			t.Errorf("check %s: got level %q, want %q", name, got[name], level)
+		}
+	}
+}
+
+func TestCheckDirtyWorktreeBare(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := git.PlainInit(dir, true); err != nil {
+		t.Fatal(err)
+	}
+	r, err := remote.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, err := checkDirtyWorktree(r, release.RemoteState{}); msg != "" || err != nil {
+		t.Errorf("checkDirtyWorktree() on bare repo = %q, %v, want nothing", msg, err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatalf("missing config file should not error: %v", err)
+	}
+	if cfg.Checks != nil {
+		t.Errorf("want zero Config, got %+v", cfg)
+	}
+
+	write := func(s string) {
+		if err := os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"checks": {"dirty": "block"}}`)
+	cfg, err = LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Checks["dirty"] != CheckBlock {
+		t.Errorf("want dirty=block, got %+v", cfg.Checks)
+	}
+
+	for _, bad := range []string{
+		`{"checks": {"dirty": "explode"}}`,
+		`{"checks": {"nonexistent": "warn"}}`,
+		`{"version_files": [{"path": "setup.cfg"}]}`,
+		`{"filter": {"exclude": [{"type": ["chore"]}]}}`,
+		`{"filter": {"exclude": [{"reason": "everything"}]}}`,
+		`{"scheme": "semver", "shceme": "calver"}`,
+		`{not json`,
+	} {
+		write(bad)
+		if _, err := LoadConfig(dir); err == nil {
+			t.Errorf("LoadConfig(%s) want error, got nil", bad)
+		}
+	}
+}
@@ -2,74 +2,192 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/manifoldco/promptui"
+	"github.com/mroth/bump/plan"
+	"github.com/mroth/bump/remote"
 )
 
 var (
-	boldStyler  = promptui.Styler(promptui.FGBold)
-	faintStyler = promptui.Styler(promptui.FGFaint)
+	boldStyler  = colored(promptui.Styler(promptui.FGBold))
+	faintStyler = colored(promptui.Styler(promptui.FGFaint))
 )
 
-type cliVersionOption struct {
-	Name        string
-	Version     semver.Version
-	Description string
+type cliVersionOption plan.Choice
+
+// customOption is the choice for entering the next version by hand.
+var customOption = cliVersionOption{
+	Name:        "custom…",
+	Description: "when you need a specific version, e.g. to realign with upstream.",
 }
 
 func (o cliVersionOption) String() string {
+	if o.Name == customOption.Name {
+		return o.Name
+	}
 	return fmt.Sprintf("%v%v",
-		o.Name, faintStyler(fmt.Sprintf(" (%v)", o.Version.String())),
+		o.Name, faintStyler(fmt.Sprintf(" (%v)", plan.VersionString(&o.Version))),
 	)
 }
 
-func prompt(currVersion *semver.Version) (*semver.Version, error) {
+// prompt interactively asks the user to select the next version following
+// currVersion from choices, reading input from stdin and drawing the UI on
+// stdout.
+//
+// If the user chooses to enter a custom version, tags is called for the
+// existing tags of the repository, which the version must not collide with.
+func prompt(currVersion *semver.Version, choices []plan.Choice, tags func() ([]string, error), stdin io.ReadCloser, stdout io.Writer) (*semver.Version, error) {
 	// promptui.IconInitial = "🚀" // default is colored ASCII question mark
-	choices := []cliVersionOption{
-		{"patch", currVersion.IncPatch(), "when you make backwards-compatible bug fixes."},
-		{"minor", currVersion.IncMinor(), "when you add functionality in a backwards-compatible manner."},
-		{"major", currVersion.IncMajor(), "when you make incompatible API changes."},
+	var options []cliVersionOption
+	for _, c := range choices {
+		options = append(options, cliVersionOption(c))
 	}
+	options = append(options, customOption)
 
 	prompt := promptui.Select{
-		Label: "Select semver increment to specify next version",
-		Items: choices,
+		Label: "Select next version",
+		Items: options,
 		Templates: &promptui.SelectTemplates{
 			Details: `{{ .Name }}: {{ .Description }}`,
 		},
-		Stdout: &bellSkipper{},
+		Stdin:  stdin,
+		Stdout: &bellSkipper{stdout},
 	}
 
 	index, _, err := prompt.Run()
 	if err != nil {
 		return nil, err
 	}
-	nextVersion := choices[index].Version
+	if options[index].Name == customOption.Name {
+		return promptCustom(currVersion, tags, stdin, stdout)
+	}
+	nextVersion := options[index].Version
 	return &nextVersion, nil
 }
 
+// promptCustom asks the user to type in the next version following
+// currVersion, which must not collide with any of the existing tags.
+func promptCustom(currVersion *semver.Version, tags func() ([]string, error), stdin io.ReadCloser, stdout io.Writer) (*semver.Version, error) {
+	existing, err := tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tags: %w", err)
+	}
+
+	prompt := promptui.Prompt{
+		Label: "Next version",
+		Validate: func(input string) error {
+			_, err := plan.ParseCustom(input, currVersion, existing)
+			return err
+		},
+		Stdin:  stdin,
+		Stdout: &bellSkipper{stdout},
+	}
+	input, err := prompt.Run()
+	if err != nil {
+		return nil, err
+	}
+	return plan.ParseCustom(input, currVersion, existing)
+}
+
+// promptBase asks the user to confirm the first of bases as the release to
+// follow, or to pick a different one.
+func promptBase(bases []plan.Base, stdin io.ReadCloser, stdout io.Writer) (plan.Base, error) {
+	items := make([]string, len(bases))
+	for i, b := range bases {
+		items[i] = fmt.Sprintf("%s%s", b.Release.GetTagName(),
+			faintStyler(fmt.Sprintf(" (published %v)", b.Release.GetPublishedAt().Format("2006 Jan 2"))))
+	}
+
+	prompt := promptui.Select{
+		Label:  "Select previous release to follow",
+		Items:  items,
+		Stdin:  stdin,
+		Stdout: &bellSkipper{stdout},
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return plan.Base{}, err
+	}
+	return bases[index], nil
+}
+
+// promptRepo asks which of several GitHub repositories found among the
+// remotes of the local clone to release, the preferred one first.
+func promptRepo(repos []remote.Repo, stdin io.ReadCloser, stdout io.Writer) (remote.Repo, error) {
+	items := make([]string, len(repos))
+	for i, r := range repos {
+		items[i] = fmt.Sprintf("%s/%s%s", r.Owner, r.Name, faintStyler(" ("+r.Remote+")"))
+	}
+
+	prompt := promptui.Select{
+		Label:  "Select repository to release",
+		Items:  items,
+		Stdin:  stdin,
+		Stdout: &bellSkipper{stdout},
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return remote.Repo{}, err
+	}
+	return repos[index], nil
+}
+
+// doneOption is the item ending promptStatusRepo and review.
+const doneOption = "done"
+
+// promptStatusRepo asks which of the repositories with unreleased changes to
+// draft a release for next, returning its index, or -1 when done.
+func promptStatusRepo(statuses []repoStatus, stdin io.ReadCloser, stdout io.Writer) (int, error) {
+	items := make([]string, len(statuses), len(statuses)+1)
+	for i, s := range statuses {
+		items[i] = fmt.Sprintf("%s/%s%s", s.Owner, s.Repo,
+			faintStyler(fmt.Sprintf(" (%d unreleased commits, suggest v%s)", s.unreleased(), plan.VersionString(&s.Next.Version))))
+	}
+	items = append(items, doneOption)
+
+	prompt := promptui.Select{
+		Label:  "Select repository to draft a release for",
+		Items:  items,
+		Stdin:  stdin,
+		Stdout: &bellSkipper{stdout},
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return -1, err
+	}
+	if index == len(statuses) {
+		return -1, nil
+	}
+	return index, nil
+}
+
 // bellSkipper implements an io.WriteCloser that skips the terminal bell
-// character (ASCII code 7), and writes the rest to os.Stderr. It is used to
-// replace readline.Stdout, that is the package used by promptui to display the
-// prompts.
+// character (ASCII code 7), and writes the rest to the wrapped writer (usually
+// os.Stderr). It is used to replace readline.Stdout, that is the package used
+// by promptui to display the prompts.
 //
 // This is a workaround for the bell issue documented in
 // https://github.com/manifoldco/promptui/issues/49.
-type bellSkipper struct{}
+type bellSkipper struct {
+	w io.Writer
+}
 
-// Write implements an io.WriterCloser over os.Stderr, but it skips the terminal
-// bell character.
+// Write implements an io.WriterCloser over w, but it skips the terminal bell
+// character.
 func (bs *bellSkipper) Write(b []byte) (int, error) {
 	const charBell = 7 // c.f. readline.CharBell
 	if len(b) == 1 && b[0] == charBell {
 		return 0, nil
 	}
-	return os.Stderr.Write(b)
+	return bs.w.Write(b)
 }
 
-// Close implements an io.WriterCloser over os.Stderr.
+// Close implements an io.WriterCloser over w, closing it if it is closeable.
 func (bs *bellSkipper) Close() error {
-	return os.Stderr.Close()
+	if c, ok := bs.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
 }
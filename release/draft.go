@@ -0,0 +1,65 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/plan"
+)
+
+// Previous returns the release to use as the previous version when drafting a
+// release from target. When target is a maintenance branch such as
+// release/1.x, that is the latest release in its line, otherwise it is the
+// latest release of the repository, as it is if the line has no releases yet.
+// Tags are parsed as versions with mode.
+func Previous(ctx context.Context, src Source, target string, mode plan.TagMode) (*github.RepositoryRelease, error) {
+	line, ok := plan.ParseLine(target)
+	if !ok {
+		return src.LatestRelease(ctx)
+	}
+	releases, err := src.Releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latest, err := plan.LatestInLine(releases, line, mode); err == nil {
+		return latest, nil
+	}
+	return src.LatestRelease(ctx)
+}
+
+// DraftURL constructs a URL to open a new draft release on GitHub for given
+// owner/repo with a semver compatible tag based on the semver.Version in the
+// tag and title fields, and an encoded body payload to prepopulate the form.
+//
+// If target is non-empty, it is set as the target_commitish the tag will be
+// created from, otherwise GitHub defaults to the default branch.
+func DraftURL(owner, repo string, version *semver.Version, target, body string) string {
+	tag := url.QueryEscape("v" + plan.VersionString(version)) // may contain + build metadata
+	u := fmt.Sprintf(
+		"https://github.com/%s/%s/releases/new?tag=%s&title=%s",
+		owner, repo, tag, tag,
+	)
+	if target != "" {
+		u += "&target=" + url.QueryEscape(target)
+	}
+	return u + "&body=" + url.QueryEscape(body)
+}
+
+// NewDraft is the API equivalent of DraftURL, returning a draft release to be
+// created with Source.CreateRelease.
+func NewDraft(version *semver.Version, target, body string) *github.RepositoryRelease {
+	tag := "v" + plan.VersionString(version)
+	release := &github.RepositoryRelease{
+		TagName: github.String(tag),
+		Name:    github.String(tag),
+		Body:    github.String(body),
+		Draft:   github.Bool(true),
+	}
+	if target != "" {
+		release.TargetCommitish = github.String(target)
+	}
+	return release
+}
@@ -0,0 +1,41 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestDraftURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		target  string
+		want    string
+	}{
+		{
+			name:    "default branch",
+			version: "1.8.3",
+			want:    "https://github.com/mroth/bump/releases/new?tag=v1.8.3&title=v1.8.3&body=hi+there",
+		},
+		{
+			name:    "maintenance branch",
+			version: "1.8.3",
+			target:  "release/1.x",
+			want:    "https://github.com/mroth/bump/releases/new?tag=v1.8.3&title=v1.8.3&target=release%2F1.x&body=hi+there",
+		},
+		{
+			name:    "build metadata",
+			version: "1.8.3+build.42",
+			want:    "https://github.com/mroth/bump/releases/new?tag=v1.8.3%2Bbuild.42&title=v1.8.3%2Bbuild.42&body=hi+there",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := semver.MustParse(tt.version)
+			if got := DraftURL("mroth", "bump", v, tt.target, "hi there"); got != tt.want {
+				t.Errorf("DraftURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package release_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/mroth/bump/plan"
+	"github.com/mroth/bump/release"
+)
+
+func ExampleDraftURL() {
+	next := semver.MustParse("1.8.3")
+	fmt.Println(release.DraftURL("mroth", "bump", next, "release/1.x", "Bug fixes."))
+	// Output: https://github.com/mroth/bump/releases/new?tag=v1.8.3&title=v1.8.3&target=release%2F1.x&body=Bug+fixes.
+}
+
+func ExamplePrevious() {
+	ctx := context.Background()
+	src := release.NewGitHubSource(nil, "mroth", "bump")
+
+	// previous release in the 1.x line, for drafting a backport release
+	prev, err := release.Previous(ctx, src, "release/1.x", plan.Lenient)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(prev.GetTagName())
+}
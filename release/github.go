@@ -0,0 +1,182 @@
+package release
+
+import (
+	"cmp"
+	"context"
+	"os"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/changelog"
+	"golang.org/x/oauth2"
+)
+
+// GitHubSource is a Source backed by the GitHub API.
+type GitHubSource struct {
+	client      *github.Client
+	owner, repo string
+}
+
+// NewGitHubSource returns a Source for owner/repo using client. If client is
+// nil, DefaultClient is used.
+func NewGitHubSource(client *github.Client, owner, repo string) *GitHubSource {
+	if client == nil {
+		client = DefaultClient()
+	}
+	return &GitHubSource{client: client, owner: owner, repo: repo}
+}
+
+// LatestRelease wraps retrieval of latest GitHub release.
+func (s *GitHubSource) LatestRelease(ctx context.Context) (*github.RepositoryRelease, error) {
+	release, _, err := s.client.Repositories.GetLatestRelease(ctx, s.owner, s.repo)
+	return release, err
+}
+
+// Releases wraps retrieval of all GitHub releases, following pagination.
+func (s *GitHubSource) Releases(ctx context.Context) ([]*github.RepositoryRelease, error) {
+	var all []*github.RepositoryRelease
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		releases, resp, err := s.client.Repositories.ListReleases(ctx, s.owner, s.repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, releases...)
+		if resp.NextPage == 0 {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// Tags wraps retrieval of all tag names, following pagination.
+func (s *GitHubSource) Tags(ctx context.Context) ([]string, error) {
+	var names []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		tags, resp, err := s.client.Repositories.ListTags(ctx, s.owner, s.repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tags {
+			names = append(names, t.GetName())
+		}
+		if resp.NextPage == 0 {
+			return names, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// Compare wraps retrieval of a commits comparison between base and head via
+// the GitHub API. If head is empty, the current default branch HEAD is used.
+//
+// Unlike git log base..head, which returns commits in reverse chronlogical
+// order, the GitHub V3 API returns in chronological order. To do what most
+// changelog generators do and show commits in reverse chronological, we just
+// mutate the actual CommitsComparison.Commits subfield prior to returning it
+// here.
+//
+// Interestingly enough, this is the exact opposite of what is claimed in the
+// GitHub API documentation, which says log is chronological, see:
+// https://developer.github.com/v3/repos/commits/#compare-two-commits.
+func (s *GitHubSource) Compare(ctx context.Context, base, head string) (*github.CommitsComparison, error) {
+	cc, _, err := s.client.Repositories.CompareCommits(ctx, s.owner, s.repo, base, cmp.Or(head, "HEAD"))
+	if cc != nil {
+		reverseCommitOrder(cc)
+	}
+	return cc, err
+}
+
+func reverseCommitOrder(cc *github.CommitsComparison) {
+	for i := len(cc.Commits)/2 - 1; i >= 0; i-- {
+		opp := len(cc.Commits) - 1 - i
+		cc.Commits[i], cc.Commits[opp] = cc.Commits[opp], cc.Commits[i]
+	}
+}
+
+// RemoteState wraps retrieval of the default branch name, along with the SHA
+// of the commit that target resolves to. If target is empty, the default
+// branch is resolved, which is what the GitHub API resolves a "HEAD" ref to.
+func (s *GitHubSource) RemoteState(ctx context.Context, target string) (RemoteState, error) {
+	rs := RemoteState{RemoteName: "origin", Target: target}
+	r, _, err := s.client.Repositories.Get(ctx, s.owner, s.repo)
+	if err != nil {
+		return rs, err
+	}
+	rs.DefaultBranch = r.GetDefaultBranch()
+	rs.HeadSHA, _, err = s.client.Repositories.GetCommitSHA1(ctx, s.owner, s.repo, cmp.Or(target, rs.DefaultBranch), "")
+	return rs, err
+}
+
+// CommitFiles wraps retrieval of a single commit, for the files it changed.
+func (s *GitHubSource) CommitFiles(ctx context.Context, sha string) ([]string, error) {
+	c, _, err := s.client.Repositories.GetCommit(ctx, s.owner, s.repo, sha)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, f := range c.Files {
+		paths = append(paths, f.GetFilename())
+	}
+	return paths, nil
+}
+
+// HasContributed wraps listing the commits in ref by author, looked up by
+// login, or email address for co-authors whose login is not known.
+func (s *GitHubSource) HasContributed(ctx context.Context, ref string, author changelog.Author) (bool, error) {
+	opts := &github.CommitsListOptions{
+		SHA:         ref,
+		Author:      cmp.Or(author.Login, author.Email),
+		ListOptions: github.ListOptions{PerPage: 1},
+	}
+	commits, _, err := s.client.Repositories.ListCommits(ctx, s.owner, s.repo, opts)
+	return len(commits) > 0, err
+}
+
+// CreateRelease wraps creation of a GitHub release. This requires the client
+// to be authorized with a token that can write to the repository.
+func (s *GitHubSource) CreateRelease(ctx context.Context, release *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	created, _, err := s.client.Repositories.CreateRelease(ctx, s.owner, s.repo, release)
+	return created, err
+}
+
+// OrgRepos wraps retrieval of the names of all repositories owned by org,
+// following pagination. Archived repositories are left out, as they are read
+// only and can't be released.
+func OrgRepos(ctx context.Context, client *github.Client, org string) ([]string, error) {
+	if client == nil {
+		client = DefaultClient()
+	}
+	var names []string
+	opts := &github.RepositoryListByOrgOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		repos, resp, err := client.Repositories.ListByOrg(ctx, org, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range repos {
+			if !r.GetArchived() {
+				names = append(names, r.GetName())
+			}
+		}
+		if resp.NextPage == 0 {
+			return names, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// DefaultClient returns a OAuth scoped Github API Client if GITHUB_TOKEN is set
+// the local environment, or an unauthorized one otherwise.
+func DefaultClient() *github.Client {
+	token, ok := os.LookupEnv("GITHUB_TOKEN")
+	if ok {
+		ctx := context.Background()
+		ts := oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: token},
+		)
+		tc := oauth2.NewClient(ctx, ts)
+		return github.NewClient(tc)
+	}
+	return github.NewClient(nil)
+}
@@ -0,0 +1,107 @@
+package release
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/changelog"
+	"github.com/mroth/bump/internal/bumptest"
+)
+
+func TestGitHubSource(t *testing.T) {
+	ctx := context.Background()
+	fake := bumptest.NewGitHub(t)
+	fake.Refs["master"] = "abc1234"
+	fake.Releases = []*github.RepositoryRelease{
+		{TagName: github.String("v2.0.0-rc.1"), Prerelease: github.Bool(true)},
+		{TagName: github.String("v1.1.0")},
+		{TagName: github.String("v1.0.0")},
+	}
+	fake.Tags = []string{"v1.0.0", "v1.1.0", "v2.0.0-rc.1"}
+	sample := bumptest.CommitsComparisons["sample"]
+	fake.Comparisons["v1.0.0...HEAD"] = sample
+	src := NewGitHubSource(fake.APIClient(), "owner", "repo")
+
+	latest, err := src.LatestRelease(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := latest.GetTagName(); got != "v1.1.0" {
+		t.Errorf("LatestRelease() = %v, want v1.1.0", got)
+	}
+
+	releases, err := src.Releases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 3 {
+		t.Errorf("Releases() returned %d, want 3", len(releases))
+	}
+
+	tags, err := src.Tags(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tags, fake.Tags) {
+		t.Errorf("Tags() = %v, want %v", tags, fake.Tags)
+	}
+
+	cc, err := src.Compare(ctx, "v1.0.0", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cc.Commits[0].GetSHA(), sample.Commits[0].GetSHA(); got != want {
+		t.Errorf("Compare() first commit = %v, want newest %v", got, want)
+	}
+
+	rs, err := src.RemoteState(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.DefaultBranch != "master" || rs.HeadSHA != "abc1234" {
+		t.Errorf("RemoteState() = %+v", rs)
+	}
+
+	fake.CommitFiles["abc1234"] = []string{"README.md", "docs/guide.md"}
+	files, err := src.CommitFiles(ctx, "abc1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(files, fake.CommitFiles["abc1234"]) {
+		t.Errorf("CommitFiles() = %v, want %v", files, fake.CommitFiles["abc1234"])
+	}
+
+	fake.Contributors = []string{"alicej"}
+	for login, want := range map[string]bool{"alicej": true, "danalee": false} {
+		got, err := src.HasContributed(ctx, "v1.0.0", changelog.Author{Login: login})
+		if err != nil || got != want {
+			t.Errorf("HasContributed(%s) = %v, %v, want %v", login, got, err, want)
+		}
+	}
+
+	created, err := src.CreateRelease(ctx, &github.RepositoryRelease{TagName: github.String("v1.2.0")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.GetHTMLURL() == "" || len(fake.Created) != 1 {
+		t.Errorf("CreateRelease() = %+v, fake saw %d", created, len(fake.Created))
+	}
+}
+
+func TestOrgRepos(t *testing.T) {
+	fake := bumptest.NewGitHub(t)
+	fake.Orgs["acme"] = []*github.Repository{
+		{Name: github.String("api")},
+		{Name: github.String("old"), Archived: github.Bool(true)},
+		{Name: github.String("web")},
+	}
+	got, err := OrgRepos(context.Background(), fake.APIClient(), "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"api", "web"}; !slices.Equal(got, want) {
+		t.Errorf("OrgRepos() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,260 @@
+package release
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/changelog"
+	"github.com/mroth/bump/plan"
+)
+
+// ErrOffline is returned for operations which cannot be done without GitHub.
+var ErrOffline = errors.New("not possible in offline mode")
+
+// LocalSource is a Source for offline use, reading everything from the local
+// clone instead of the GitHub API. Tags stand in for releases.
+type LocalSource struct {
+	repo        *git.Repository
+	owner, name string // GitHub owner/repo, for constructing URLs
+	remoteName  string // remote pointing at GitHub
+	tagMode     plan.TagMode
+}
+
+// NewLocalSource returns a Source reading from the local clone r of the GitHub
+// repository owner/repo, which is used for constructing URLs.
+func NewLocalSource(r *git.Repository, owner, repo string) *LocalSource {
+	return &LocalSource{repo: r, owner: owner, name: repo, remoteName: "origin", tagMode: plan.Lenient}
+}
+
+// WithRemote sets the name of the git remote pointing at the GitHub
+// repository, which is "origin" by default, and returns s.
+func (s *LocalSource) WithRemote(name string) *LocalSource {
+	s.remoteName = name
+	return s
+}
+
+// WithTagMode sets how strictly tags are parsed as versions when looking for
+// the latest release, which is plan.Lenient by default, and returns s.
+func (s *LocalSource) WithTagMode(mode plan.TagMode) *LocalSource {
+	s.tagMode = mode
+	return s
+}
+
+// Releases returns a pseudo release for each tag in the local repository,
+// published at the tagger date for annotated tags, or the date of the tagged
+// commit for lightweight tags.
+func (s *LocalSource) Releases(context.Context) ([]*github.RepositoryRelease, error) {
+	r := s.repo
+	tags, err := r.Tags()
+	if err != nil {
+		return nil, err
+	}
+	var releases []*github.RepositoryRelease
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		var published time.Time
+		if tag, err := r.TagObject(ref.Hash()); err == nil {
+			published = tag.Tagger.When
+		} else if commit, err := r.CommitObject(ref.Hash()); err == nil {
+			published = commit.Committer.When
+		}
+		releases = append(releases, &github.RepositoryRelease{
+			TagName:     github.String(ref.Name().Short()),
+			PublishedAt: &github.Timestamp{Time: published},
+		})
+		return nil
+	})
+	return releases, err
+}
+
+// LatestRelease returns the local tag with the highest semantic version, as
+// parsed with the tag mode of s, as a pseudo release.
+func (s *LocalSource) LatestRelease(ctx context.Context) (*github.RepositoryRelease, error) {
+	releases, err := s.Releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	latest := plan.Highest(releases, s.tagMode, func(*semver.Version) bool { return true })
+	if latest == nil {
+		return nil, errors.New("no semver tags found in local repository")
+	}
+	return latest, nil
+}
+
+// Tags returns the names of all tags in the local repository.
+func (s *LocalSource) Tags(context.Context) ([]string, error) {
+	tags, err := s.repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		names = append(names, ref.Name().Short())
+		return nil
+	})
+	return names, err
+}
+
+// Compare builds a commits comparison between base and head from the local
+// repository history, equivalent to what the GitHub API would return. If head
+// is empty, the local HEAD is used.
+//
+// Commits are in reverse chronological order, like git log.
+func (s *LocalSource) Compare(_ context.Context, baseRev, headRev string) (*github.CommitsComparison, error) {
+	headRev = cmp.Or(headRev, "HEAD")
+	base, err := s.repo.ResolveRevision(plumbing.Revision(baseRev))
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", baseRev, err)
+	}
+	head, err := s.repo.ResolveRevision(plumbing.Revision(headRev))
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", headRev, err)
+	}
+	commits, err := commitsBetween(s.repo, *base, *head)
+	if err != nil {
+		return nil, err
+	}
+
+	cc := &github.CommitsComparison{
+		HTMLURL: github.String(fmt.Sprintf(
+			"https://github.com/%s/%s/compare/%s...%s", s.owner, s.name, baseRev, headRev,
+		)),
+		TotalCommits: github.Int(len(commits)),
+	}
+	for _, c := range commits {
+		var parents []github.Commit
+		for _, p := range c.ParentHashes {
+			parents = append(parents, github.Commit{SHA: github.String(p.String())})
+		}
+		cc.Commits = append(cc.Commits, github.RepositoryCommit{
+			SHA:     github.String(c.Hash.String()),
+			Parents: parents,
+			Commit: &github.Commit{
+				Message: github.String(c.Message),
+				Author: &github.CommitAuthor{
+					Name:  github.String(c.Author.Name),
+					Email: github.String(c.Author.Email),
+					Date:  &c.Author.When,
+				},
+			},
+		})
+	}
+	return cc, nil
+}
+
+// commitsBetween returns the commits reachable from head which are not
+// reachable from base, similar to git log base..head, newest first.
+func commitsBetween(r *git.Repository, base, head plumbing.Hash) ([]*object.Commit, error) {
+	headCommit, err := r.CommitObject(head)
+	if err != nil {
+		return nil, err
+	}
+	baseCommit, err := r.CommitObject(base)
+	if err != nil {
+		return nil, err
+	}
+	mergeBases, err := headCommit.MergeBase(baseCommit)
+	if err != nil {
+		return nil, err
+	}
+	ignore := make([]plumbing.Hash, len(mergeBases))
+	for i, b := range mergeBases {
+		ignore[i] = b.Hash
+	}
+
+	var commits []*object.Commit
+	err = object.NewCommitIterCTime(headCommit, nil, ignore).ForEach(func(c *object.Commit) error {
+		commits = append(commits, c)
+		return nil
+	})
+	return commits, err
+}
+
+// CommitFiles returns the paths of the files changed by the commit sha,
+// compared to its first parent.
+func (s *LocalSource) CommitFiles(_ context.Context, sha string) ([]string, error) {
+	c, err := s.repo.CommitObject(plumbing.NewHash(sha))
+	if err != nil {
+		return nil, err
+	}
+	stats, err := c.Stats()
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(stats))
+	for i, st := range stats {
+		paths[i] = st.Name
+	}
+	return paths, nil
+}
+
+// HasContributed looks through the commits in ref for one by author, matching
+// by email address. Logins are unknown locally, so the email address of the
+// author is needed.
+func (s *LocalSource) HasContributed(_ context.Context, ref string, author changelog.Author) (bool, error) {
+	if author.Email == "" {
+		return false, fmt.Errorf("no email address for %s", author)
+	}
+	hash, err := s.repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return false, fmt.Errorf("resolving %s: %w", ref, err)
+	}
+	commits, err := s.repo.Log(&git.LogOptions{From: *hash})
+	if err != nil {
+		return false, err
+	}
+	var found bool
+	err = commits.ForEach(func(c *object.Commit) error {
+		if strings.EqualFold(c.Author.Email, author.Email) {
+			found = true
+			return storer.ErrStop
+		}
+		return nil
+	})
+	return found, err
+}
+
+// RemoteState determines the view of the repository on GitHub from the
+// remote-tracking refs of the GitHub remote, as of the last fetch.
+func (s *LocalSource) RemoteState(_ context.Context, target string) (RemoteState, error) {
+	r, remoteName := s.repo, s.remoteName
+	rs := RemoteState{RemoteName: remoteName, Target: target}
+
+	// origin/HEAD records the default branch of the remote when cloned
+	ref, err := r.Reference(plumbing.NewRemoteHEADReferenceName(remoteName), false)
+	if err == nil && ref.Type() == plumbing.SymbolicReference {
+		rs.DefaultBranch = strings.TrimPrefix(ref.Target().Short(), remoteName+"/")
+	} else {
+		head, err := r.Head()
+		if err != nil {
+			return rs, err
+		}
+		// not cloned, so assume whatever is checked out
+		rs.DefaultBranch = head.Name().Short()
+	}
+
+	rev := target
+	if branch := rs.TargetBranch(); branch != "" {
+		rev = plumbing.NewRemoteReferenceName(remoteName, branch).String()
+	}
+	hash, err := r.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return rs, fmt.Errorf("resolving %s: %w", rev, err)
+	}
+	rs.HeadSHA = hash.String()
+	return rs, nil
+}
+
+// CreateRelease always fails, as releases only exist on GitHub.
+func (s *LocalSource) CreateRelease(context.Context, *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	return nil, ErrOffline
+}
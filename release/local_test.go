@@ -0,0 +1,150 @@
+package release
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/mroth/bump/changelog"
+	"github.com/mroth/bump/internal/bumptest"
+	"github.com/mroth/bump/plan"
+)
+
+func TestLocalLatestReleaseTagMode(t *testing.T) {
+	tr := bumptest.NewRepo(t)
+	tr.Tag("v1.1.0", tr.Commit("a.txt", "a", "initial"), false)
+	tr.Tag("v1.2", tr.Commit("b.txt", "b", "feat: second"), false)
+
+	ctx := context.Background()
+	for mode, want := range map[plan.TagMode]string{plan.Lenient: "v1.2", plan.Strict: "v1.1.0"} {
+		latest, err := NewLocalSource(tr.Repository, "owner", "repo").WithTagMode(mode).LatestRelease(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := latest.GetTagName(); got != want {
+			t.Errorf("%s LatestRelease() = %v, want %v", mode, got, want)
+		}
+	}
+}
+
+func TestLocalRelease(t *testing.T) {
+	tr := bumptest.NewRepo(t)
+	v1 := tr.Commit("a.txt", "a", "initial")
+	tr.Tag("v1.0.0", v1, true)
+	tr.Commit("b.txt", "b", "feat: second\n\nwith a body")
+	v11 := tr.Commit("c.txt", "c", "fix: third")
+	tr.Tag("v1.1.0", v11, false)
+	tr.Tag("nightly", v11, false)
+	tr.Commit("d.txt", "d", "feat: fourth")
+	tr.Commit("e.txt", "e", "docs: fifth")
+
+	ctx := context.Background()
+	src := NewLocalSource(tr.Repository, "owner", "repo")
+
+	latest, err := src.LatestRelease(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := latest.GetTagName(); got != "v1.1.0" {
+		t.Errorf("latest tag = %v, want v1.1.0", got)
+	}
+
+	latest, err = Previous(ctx, src, "release/1.0", plan.Lenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := latest.GetTagName(); got != "v1.0.0" {
+		t.Errorf("latest 1.0.x tag = %v, want v1.0.0", got)
+	}
+
+	latest, err = Previous(ctx, src, "release/5.x", plan.Lenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := latest.GetTagName(); got != "v1.1.0" {
+		t.Errorf("latest tag of a line without releases = %v, want v1.1.0", got)
+	}
+
+	tags, err := src.Tags(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 3 {
+		t.Errorf("Tags() = %v, want 3 tags", tags)
+	}
+
+	cc, err := src.Compare(ctx, "v1.0.0", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range cc.Commits {
+		got = append(got, strings.SplitN(c.Commit.GetMessage(), "\n", 2)[0])
+	}
+	want := []string{"docs: fifth", "feat: fourth", "fix: third", "feat: second"}
+	if len(got) != len(want) {
+		t.Fatalf("commits = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("commit[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if got, want := cc.GetHTMLURL(), "https://github.com/owner/repo/compare/v1.0.0...HEAD"; got != want {
+		t.Errorf("HTMLURL = %v, want %v", got, want)
+	}
+
+	files, err := src.CommitFiles(ctx, cc.Commits[1].GetSHA())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0] != "d.txt" {
+		t.Errorf("CommitFiles() = %v, want [d.txt]", files)
+	}
+
+	for email, want := range map[string]bool{"TEST@example.com": true, "new@example.com": false} {
+		got, err := src.HasContributed(ctx, "v1.0.0", changelog.Author{Email: email})
+		if err != nil || got != want {
+			t.Errorf("HasContributed(%s) = %v, %v, want %v", email, got, err, want)
+		}
+	}
+}
+
+func TestLocalRemoteState(t *testing.T) {
+	tr := bumptest.NewRepo(t)
+	h := tr.Commit("a.txt", "a", "initial")
+	tr.SetRef(plumbing.NewRemoteReferenceName("origin", "master"), h)
+
+	ctx := context.Background()
+	src := NewLocalSource(tr.Repository, "owner", "repo")
+
+	rs, err := src.RemoteState(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.DefaultBranch != "master" || rs.HeadSHA != h.String() {
+		t.Errorf("got %+v, want default master at %v", rs, h)
+	}
+
+	// origin/HEAD takes precedence over the local branch name
+	tr.SetRef(plumbing.NewRemoteReferenceName("origin", "main"), h)
+	err = tr.Storer.SetReference(plumbing.NewSymbolicReference(
+		plumbing.NewRemoteHEADReferenceName("origin"),
+		plumbing.NewRemoteReferenceName("origin", "main"),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err = src.RemoteState(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.DefaultBranch != "main" {
+		t.Errorf("DefaultBranch = %v, want main", rs.DefaultBranch)
+	}
+
+	if _, err := src.RemoteState(ctx, "release/9.x"); err == nil {
+		t.Error("want error for target with no remote-tracking ref")
+	}
+}
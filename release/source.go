@@ -0,0 +1,68 @@
+// Package release drafts new GitHub releases, and retrieves the history they
+// are based on from either the GitHub API or a local clone.
+package release
+
+import (
+	"context"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/changelog"
+	"github.com/mroth/bump/plan"
+)
+
+// Source provides the release history of a single repository, along
+// with the ability to create new releases for it.
+//
+// The GitHub API is the primary implementation, with the local git clone as
+// an alternative for offline use. Results use the go-github types regardless
+// of where they came from, so the rest of the program does not need to care.
+type Source interface {
+	// LatestRelease returns the most recent published release.
+	LatestRelease(ctx context.Context) (*github.RepositoryRelease, error)
+
+	// Releases returns all releases, in no particular order.
+	Releases(ctx context.Context) ([]*github.RepositoryRelease, error)
+
+	// Tags returns the names of all tags, in no particular order.
+	Tags(ctx context.Context) ([]string, error)
+
+	// Compare returns the commits in head which are not in base, in reverse
+	// chronological order. An empty head means the default branch HEAD.
+	Compare(ctx context.Context, base, head string) (*github.CommitsComparison, error)
+
+	// RemoteState returns the state of the repository on GitHub for drafting
+	// a release from target, to compare a local working copy against.
+	RemoteState(ctx context.Context, target string) (RemoteState, error)
+
+	// CommitFiles returns the paths of the files changed by the commit sha,
+	// which comparisons leave out.
+	CommitFiles(ctx context.Context, sha string) ([]string, error)
+
+	// HasContributed reports whether author has any commits in ref, e.g. the
+	// previous release, matching them by login or email address.
+	HasContributed(ctx context.Context, ref string, author changelog.Author) (bool, error)
+
+	// CreateRelease creates a new release, returning it as created.
+	CreateRelease(ctx context.Context, release *github.RepositoryRelease) (*github.RepositoryRelease, error)
+}
+
+// RemoteState is the view of a repository on GitHub that a release would be
+// drafted from.
+type RemoteState struct {
+	RemoteName    string // name of the local remote pointing at GitHub
+	DefaultBranch string // default branch of the repository on GitHub
+	Target        string // branch or sha the release is drafted from, if not default
+	HeadSHA       string // commit that the target resolves to
+}
+
+// TargetBranch returns the name of the branch the release is drafted from, or
+// an empty string if the target is a specific commit.
+func (rs RemoteState) TargetBranch() string {
+	if rs.Target == "" {
+		return rs.DefaultBranch
+	}
+	if plan.IsSHA(rs.Target) {
+		return ""
+	}
+	return rs.Target
+}
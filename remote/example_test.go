@@ -0,0 +1,13 @@
+package remote_test
+
+import (
+	"fmt"
+
+	"github.com/mroth/bump/remote"
+)
+
+func ExampleParse() {
+	owner, repo, ok := remote.Parse("git@github.com:mroth/bump.git")
+	fmt.Println(owner, repo, ok)
+	// Output: mroth bump true
+}
@@ -0,0 +1,213 @@
+// Package remote detects which GitHub repository a local git clone belongs to.
+//
+// Detection only reads the local git configuration via go-git, and does not
+// require git to be installed or make any network requests.
+package remote
+
+import (
+	"cmp"
+	"errors"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// DefaultPriority is the order in which remotes are preferred by Detect: when
+// working in a fork, upstream is the repository releases are made from.
+var DefaultPriority = []string{"upstream", "origin"}
+
+// Repo is a GitHub repository found as a remote of a local clone.
+type Repo struct {
+	Remote string // name of the git remote, e.g. "origin"
+	URL    string // URL of the remote
+	Owner  string
+	Name   string
+}
+
+// Detector finds the GitHub repositories which are remotes of a local clone.
+type Detector struct {
+	// Priority lists remote names to prefer, in order. Other remotes follow
+	// in alphabetical order.
+	Priority []string
+	// SSH, if set, resolves host aliases in remote URLs, such as
+	// "github-work:org/repo".
+	SSH *SSHConfig
+}
+
+// Open opens the git repository containing path, like git does: searching
+// upward from path for a .git directory, and following .git files to the
+// repository of a linked worktree or submodule. A bare repository at path is
+// opened as it is.
+func Open(path string) (*git.Repository, error) {
+	r, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		if bare, bareErr := git.PlainOpen(path); bareErr == nil {
+			return bare, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// Root returns the root directory of the working tree of r, which was opened
+// from dir. A bare repository has no working tree, so its root is dir.
+func Root(r *git.Repository, dir string) (string, error) {
+	wt, err := r.Worktree()
+	if errors.Is(err, git.ErrIsBareRepository) {
+		return dir, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return wt.Filesystem.Root(), nil
+}
+
+// Find returns the GitHub repositories found in the remotes of the git
+// repository containing path, in order of preference. Each repository is listed only
+// once, for its most preferred remote.
+func (d Detector) Find(path string) ([]Repo, error) {
+	gitRepo, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	remotes, err := gitRepo.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	rank := func(name string) int {
+		if i := slices.Index(d.Priority, name); i != -1 {
+			return i
+		}
+		return len(d.Priority)
+	}
+	slices.SortFunc(remotes, func(a, b *git.Remote) int {
+		an, bn := a.Config().Name, b.Config().Name
+		return cmp.Or(cmp.Compare(rank(an), rank(bn)), cmp.Compare(an, bn))
+	})
+
+	var repos []Repo
+	for _, r := range remotes {
+		for _, u := range r.Config().URLs {
+			owner, name, ok := parse(u, d.SSH.HostName)
+			if !ok || slices.ContainsFunc(repos, func(r Repo) bool {
+				return strings.EqualFold(r.Owner, owner) && strings.EqualFold(r.Name, name)
+			}) {
+				continue
+			}
+			repos = append(repos, Repo{r.Config().Name, u, owner, name})
+		}
+	}
+	return repos, nil
+}
+
+// Detect attempts to detect whether a given path is part of a git repository
+// that has a GitHub remote, and if so, returns the owner and repo name. When
+// there are several, the first remote in DefaultPriority wins.
+//
+// Errors returned are likely just be a simple "not in git repo" etc and should
+// be considered informational rather than fatal.
+func Detect(path string) (owner, repo string, err error) {
+	ssh, err := LoadSSHConfig(DefaultSSHConfigPath())
+	if err != nil {
+		return "", "", err
+	}
+	repos, err := Detector{Priority: DefaultPriority, SSH: ssh}.Find(path)
+	if err != nil {
+		return "", "", err
+	}
+	if len(repos) == 0 {
+		return "", "", errors.New("no remote with a GitHub URL found")
+	}
+	return repos[0].Owner, repos[0].Name, nil
+}
+
+// UpstreamBranch returns the name of the branch on remoteName that the current
+// branch of the git repository containing path tracks, or an empty string if it does
+// not track a branch on that remote (or HEAD is detached).
+func UpstreamBranch(path, remoteName string) (string, error) {
+	gitRepo, err := Open(path)
+	if err != nil {
+		return "", err
+	}
+	head, err := gitRepo.Head()
+	if err != nil {
+		return "", err
+	}
+	if !head.Name().IsBranch() {
+		return "", nil
+	}
+	bc, err := gitRepo.Branch(head.Name().Short())
+	if errors.Is(err, git.ErrBranchNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if bc.Remote != remoteName || !bc.Merge.IsBranch() {
+		return "", nil
+	}
+	return bc.Merge.Short(), nil
+}
+
+// Parse parses string remoteURL against known patterns matching GitHub
+// remotes and returns the owner and repo, along with a boolean ok indicating
+// whether a match was found.
+//
+// Possible GitHub remote formats:
+//
+//	https://github.com/mroth/bump.git
+//	https://user@github.com/mroth/bump.git
+//	git@github.com:mroth/bump.git
+//	ssh://git@github.com/mroth/bump.git
+//	git://github.com/mroth/bump.git
+func Parse(remoteURL string) (owner, repo string, ok bool) {
+	return parse(remoteURL, nil)
+}
+
+var (
+	schemePattern = regexp.MustCompile(`^[a-z][a-z0-9+.-]*://`)
+	scpPattern    = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+)
+
+// parse is Parse, resolving the host of remoteURL with hostName if not nil.
+func parse(remoteURL string, hostName func(alias string) string) (owner, repo string, ok bool) {
+	var host, path string
+	if schemePattern.MatchString(remoteURL) {
+		u, err := url.Parse(remoteURL)
+		if err != nil {
+			return
+		}
+		switch u.Scheme {
+		case "https", "http", "ssh", "git", "git+ssh", "ssh+git":
+		default:
+			return
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		m := scpPattern.FindStringSubmatch(remoteURL)
+		if m == nil {
+			return
+		}
+		host, path = m[1], m[2]
+	}
+
+	if hostName != nil {
+		host = hostName(host)
+	}
+	if !strings.EqualFold(host, "github.com") {
+		return
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	segments := strings.Split(path, "/")
+	if len(segments) != 2 || segments[0] == "" || segments[1] == "" {
+		return
+	}
+	return segments[0], segments[1], true
+}
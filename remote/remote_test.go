@@ -0,0 +1,330 @@
+package remote
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/mroth/bump/internal/bumptest"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		remoteURL string
+		wantOwner string
+		wantRepo  string
+		wantOk    bool
+	}{
+		{
+			name:      "GitHub_HTTPS",
+			remoteURL: "https://github.com/mroth/bump.git",
+			wantOwner: "mroth",
+			wantRepo:  "bump",
+			wantOk:    true,
+		},
+		{
+			name:      "GitHub_HTTPS_noExtension",
+			remoteURL: "https://github.com/mroth/bump",
+			wantOwner: "mroth",
+			wantRepo:  "bump",
+			wantOk:    true,
+		},
+		{
+			name:      "GitHub_SSH",
+			remoteURL: "git@github.com:mroth/bump.git",
+			wantOwner: "mroth",
+			wantRepo:  "bump",
+			wantOk:    true,
+		},
+		{
+			name:      "GitHub_HTTPS_user",
+			remoteURL: "https://mroth@github.com/mroth/bump.git",
+			wantOwner: "mroth",
+			wantRepo:  "bump",
+			wantOk:    true,
+		},
+		{
+			name:      "GitHub_SSH_URL",
+			remoteURL: "ssh://git@github.com/mroth/bump.git",
+			wantOwner: "mroth",
+			wantRepo:  "bump",
+			wantOk:    true,
+		},
+		{
+			name:      "GitHub_SSH_URL_port",
+			remoteURL: "ssh://git@github.com:22/mroth/bump/",
+			wantOwner: "mroth",
+			wantRepo:  "bump",
+			wantOk:    true,
+		},
+		{
+			name:      "GitHub_git",
+			remoteURL: "git://github.com/mroth/bump.git",
+			wantOwner: "mroth",
+			wantRepo:  "bump",
+			wantOk:    true,
+		},
+		{
+			name:      "unresolved_alias",
+			remoteURL: "github-work:mroth/bump.git",
+			wantOk:    false,
+		},
+		{
+			name:      "other_host",
+			remoteURL: "git@gitlab.com:mroth/bump.git",
+			wantOk:    false,
+		},
+		{
+			name:      "too_many_segments",
+			remoteURL: "https://github.com/mroth/bump/tree/main",
+			wantOk:    false,
+		},
+		{
+			name:      "local_path",
+			remoteURL: "/srv/git/bump.git",
+			wantOk:    false,
+		},
+		// negative cases: near-miss hostnames should not match
+		{
+			name:      "nearMiss_HTTPS_dotReplaced",
+			remoteURL: "https://githubXcom/mroth/bump.git",
+			wantOk:    false,
+		},
+		{
+			name:      "nearMiss_SSH_dotReplaced",
+			remoteURL: "git@githubXcom:mroth/bump.git",
+			wantOk:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOwner, gotRepo, gotOk := Parse(tt.remoteURL)
+			if gotOwner != tt.wantOwner {
+				t.Errorf("Parse() gotOwner = %v, want %v", gotOwner, tt.wantOwner)
+			}
+			if gotRepo != tt.wantRepo {
+				t.Errorf("Parse() gotRepo = %v, want %v", gotRepo, tt.wantRepo)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("Parse() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	owner, repo, err := Detect("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "mroth/bump"
+	actual := owner + "/" + repo
+	if expect != actual {
+		t.Errorf("want %v got %v", expect, actual)
+	}
+}
+
+func TestDetectorFind(t *testing.T) {
+	tr := bumptest.NewRepo(t)
+	tr.AddRemote("origin", "git@github.com:me/bump.git")
+	tr.AddRemote("upstream", "https://github.com/mroth/bump.git")
+	tr.AddRemote("mirror", "https://gitlab.com/mroth/bump.git")
+	tr.AddRemote("work", "github-work:acme/bump.git")
+	tr.AddRemote("another", "ssh://git@github.com/mroth/bump") // same as upstream
+
+	ssh, err := ParseSSHConfig(strings.NewReader("Host github-work\n  HostName github.com\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos, err := Detector{Priority: DefaultPriority, SSH: ssh}.Find(tr.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range repos {
+		got = append(got, r.Remote+"="+r.Owner+"/"+r.Name)
+	}
+	want := []string{"upstream=mroth/bump", "origin=me/bump", "work=acme/bump"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find() = %v, want %v", got, want)
+	}
+}
+
+func TestOpenBare(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := git.PlainInit(dir, true); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() bare repo error = %v", err)
+	}
+	if root, err := Root(r, dir); err != nil || root != dir {
+		t.Errorf("Root() = %q, %v, want %q", root, err, dir)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	tr := bumptest.NewRepo(t)
+	tr.AddRemote("origin", "https://github.com/mroth/bump.git")
+	tr.Commit("a.txt", "a", "initial")
+	subdir := filepath.Join(tr.Dir, "sub", "dir")
+	if err := os.MkdirAll(subdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	worktree := tr.AddWorktree("feature")
+
+	tests := []struct {
+		name, path, wantRoot string
+	}{
+		{"root", tr.Dir, tr.Dir},
+		{"subdir", subdir, tr.Dir},
+		{"worktree", worktree, worktree},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Open(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if root, err := Root(r, tt.path); err != nil || root != tt.wantRoot {
+				t.Errorf("Root() = %q, %v, want %q", root, err, tt.wantRoot)
+			}
+			owner, repo, err := Detect(tt.path)
+			if err != nil || owner != "mroth" || repo != "bump" {
+				t.Errorf("Detect() = %q, %q, %v, want mroth/bump", owner, repo, err)
+			}
+		})
+	}
+}
+
+func TestSSHConfig(t *testing.T) {
+	c, err := ParseSSHConfig(strings.NewReader(`
+# work account
+Host github-work gh-*
+    HostName github.com
+    IdentityFile ~/.ssh/id_work
+
+Host=gh-personal
+    HostName=example.com
+
+Host *.internal !secret.internal
+	HostName %h.example.com
+
+Match host foo
+    HostName nope.example.com
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for alias, want := range map[string]string{
+		"github-work":     "github.com",
+		"gh-personal":     "github.com", // first match wins
+		"git.internal":    "git.internal.example.com",
+		"secret.internal": "secret.internal",
+		"github.com":      "github.com",
+		"foo":             "foo",
+	} {
+		if got := c.HostName(alias); got != want {
+			t.Errorf("HostName(%q) = %q, want %q", alias, got, want)
+		}
+	}
+}
+
+func TestUpstreamBranch(t *testing.T) {
+	tr := bumptest.NewRepo(t)
+	tr.Commit("a.txt", "a", "initial")
+
+	got, err := UpstreamBranch(tr.Dir, "origin")
+	if err != nil || got != "" {
+		t.Errorf("untracked branch: got %q, %v; want empty", got, err)
+	}
+
+	tr.Checkout("release/1.x", true)
+	err = tr.CreateBranch(&config.Branch{
+		Name:   "release/1.x",
+		Remote: "origin",
+		Merge:  plumbing.NewBranchReferenceName("release/1.x"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = UpstreamBranch(tr.Dir, "origin")
+	if err != nil || got != "release/1.x" {
+		t.Errorf("tracking branch: got %q, %v; want %q", got, err, "release/1.x")
+	}
+
+	got, err = UpstreamBranch(tr.Dir, "upstream")
+	if err != nil || got != "" {
+		t.Errorf("tracking other remote: got %q, %v; want empty", got, err)
+	}
+}
+
+// The detectRemoteURL implementations below compare reading the origin remote
+// with go-git against shelling out to git, which informed using go-git. They
+// are only kept for their benchmarks.
+
+// detectRemoteURL implementation using go-git
+//
+// will work even if git is not installed on users machine
+// one more dependency to track and keep up to date
+//
+// go-git adds ~4mb to macOS binary size (from 12MB->16MB ugh)
+// benchmarks at 0.1 ms/op
+func _detectRemoteURL_GoGit(path string) (string, error) {
+	gitRepo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", err
+	}
+	remote, err := gitRepo.Remote("origin")
+	if err != nil {
+		return "", err
+	}
+	return remote.Config().URLs[0], nil
+}
+
+// detectRemoteURL implementation shelling out to local copy of git
+//
+// requires git to be installed on machine
+// uses os/exec from standard library, does not add a dependency
+//
+// os/exec adds 242KB to macOS binary size
+// bytes adds 218kb
+// benchmarks at 5.1 ms/op
+//
+// NOTE: only here for benchmarking purposes, against the go-git version.
+// FIXME: Does not respect path (known issue, would need address if using this in future).
+func _detectRemoteURL_LocalGit(path string) (string, error) {
+	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(output)), nil
+}
+
+func Benchmark_detectRemoteURL_GoGit(b *testing.B) {
+	for b.Loop() {
+		_detectRemoteURL_GoGit("..")
+	}
+}
+
+func Benchmark_detectRemoteURL_LocalGit(b *testing.B) {
+	for b.Loop() {
+		_detectRemoteURL_LocalGit("..")
+	}
+}
+
+func Benchmark_Parse(b *testing.B) {
+	for b.Loop() {
+		Parse("https://github.com/mroth/bump.git")
+	}
+}
@@ -0,0 +1,122 @@
+package remote
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SSHConfig holds the host aliases defined in an OpenSSH client config file,
+// such as
+//
+//	Host github-work
+//	    HostName github.com
+//	    IdentityFile ~/.ssh/id_work
+//
+// Only the Host and HostName keywords are understood, everything else
+// (including Match blocks and Include) is ignored.
+type SSHConfig struct {
+	hosts []sshHost
+}
+
+type sshHost struct {
+	patterns []string
+	hostName string
+}
+
+// DefaultSSHConfigPath returns the path of the current user's SSH config
+// file, ~/.ssh/config, or an empty string if the home directory is unknown.
+func DefaultSSHConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ssh", "config")
+}
+
+// LoadSSHConfig reads the SSH config file at path. A missing file is not an
+// error, and results in an SSHConfig which resolves no aliases.
+func LoadSSHConfig(path string) (*SSHConfig, error) {
+	if path == "" {
+		return &SSHConfig{}, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &SSHConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseSSHConfig(f)
+}
+
+// ParseSSHConfig parses the contents of an SSH config file.
+func ParseSSHConfig(r io.Reader) (*SSHConfig, error) {
+	var (
+		c       SSHConfig
+		current *sshHost
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// keyword and arguments are separated by whitespace and/or "="
+		i := strings.IndexAny(line, " \t=")
+		if i == -1 {
+			continue
+		}
+		keyword := line[:i]
+		args := strings.TrimSpace(strings.TrimPrefix(strings.TrimLeft(line[i:], " \t"), "="))
+
+		switch strings.ToLower(keyword) {
+		case "host":
+			c.hosts = append(c.hosts, sshHost{patterns: strings.Fields(args)})
+			current = &c.hosts[len(c.hosts)-1]
+		case "match":
+			current = nil
+		case "hostname":
+			if current != nil && current.hostName == "" {
+				current.hostName = args
+			}
+		}
+	}
+	return &c, scanner.Err()
+}
+
+// HostName returns the real host name for alias, which is alias itself if no
+// Host block with a HostName matches it. As in ssh, the first match wins.
+func (c *SSHConfig) HostName(alias string) string {
+	if c == nil {
+		return alias
+	}
+	for _, h := range c.hosts {
+		if h.hostName != "" && h.matches(alias) {
+			return strings.ReplaceAll(h.hostName, "%h", alias)
+		}
+	}
+	return alias
+}
+
+// matches reports whether alias matches the patterns of the Host line, where
+// patterns may use * and ? wildcards, and be negated with !.
+func (h sshHost) matches(alias string) bool {
+	var matched bool
+	for _, p := range h.patterns {
+		negated := strings.HasPrefix(p, "!")
+		if ok, _ := path.Match(strings.TrimPrefix(p, "!"), alias); ok {
+			if negated {
+				return false
+			}
+			matched = true
+		}
+	}
+	return matched
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/manifoldco/promptui"
+	"github.com/mroth/bump/release"
+)
+
+// reviewItem is a commit in the review of the changelog, or the item ending
+// the review if commit is nil.
+type reviewItem struct {
+	commit   *github.RepositoryCommit
+	included bool     // listed in the release notes
+	files    []string // files changed by the commit, once looked up
+}
+
+// String formats the item as a line of the review list.
+func (i *reviewItem) String() string {
+	if i.commit == nil {
+		return doneOption
+	}
+	subject, _, _ := strings.Cut(i.commit.GetCommit().GetMessage(), "\n")
+	mark := "[x]"
+	if !i.included {
+		mark = "[ ]"
+	}
+	return fmt.Sprintf("%s %s%s", mark, subject, faintStyler(fmt.Sprintf(" %.7s", i.commit.GetSHA())))
+}
+
+// maxReviewFiles is how many of the files changed by a commit are listed in
+// its details, before summing up the rest.
+const maxReviewFiles = 10
+
+// Details expands the item below the review list: the author and the full
+// message of the commit, and the files it changes once looked up.
+func (i *reviewItem) Details() string {
+	if i.commit == nil {
+		return ""
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%s by %s\n\n%s\n",
+		i.commit.GetSHA(), i.commit.GetCommit().GetAuthor().GetName(),
+		strings.TrimSpace(i.commit.GetCommit().GetMessage()))
+	if i.files != nil {
+		fmt.Fprintf(&buf, "\n%d files changed:\n", len(i.files))
+		for _, f := range i.files[:min(maxReviewFiles, len(i.files))] {
+			fmt.Fprintf(&buf, "  %s\n", f)
+		}
+		if more := len(i.files) - maxReviewFiles; more > 0 {
+			fmt.Fprintf(&buf, "  ...and %d more\n", more)
+		}
+	}
+	return buf.String()
+}
+
+// Actions on a commit picked in the review.
+const (
+	reviewLeaveOut = "leave out of release notes"
+	reviewPutBack  = "put back in release notes"
+	reviewFiles    = "show changed files"
+	reviewBack     = "back"
+)
+
+// review lets the user look through every commit in comparison before
+// choosing the next version, expanding the full message of each and the
+// files it changes, and leave any out of the release notes. It returns a copy
+// of comparison with only the commits left in. height is that of the
+// terminal, to fit the list to, or zero if unknown.
+func review(ctx context.Context, source release.Source, comparison *github.CommitsComparison, height int, stdin io.ReadCloser, stdout io.Writer) (*github.CommitsComparison, error) {
+	items := make([]*reviewItem, len(comparison.Commits), len(comparison.Commits)+1)
+	for i := range comparison.Commits {
+		items[i] = &reviewItem{commit: &comparison.Commits[i], included: true}
+	}
+	items = append(items, &reviewItem{})
+
+	size := 10
+	if height > 0 {
+		size = max(5, height-15) // leaving room for the details
+	}
+	cursor, scroll := 0, 0
+	for {
+		var included int
+		for _, item := range items {
+			if item.included {
+				included++
+			}
+		}
+		list := promptui.Select{
+			Label: fmt.Sprintf("Review commits (%d of %d in release notes)", included, len(comparison.Commits)),
+			Items: items,
+			Size:  size,
+			Templates: &promptui.SelectTemplates{
+				Details: `{{ .Details }}`,
+			},
+			HideSelected: true,
+			Stdin:        stdin,
+			Stdout:       &bellSkipper{stdout},
+		}
+		index, _, err := list.RunCursorAt(cursor, scroll)
+		if err != nil {
+			return nil, err
+		}
+		item := items[index]
+		if item.commit == nil {
+			break
+		}
+		cursor, scroll = index, list.ScrollPosition()
+
+		actions := []string{reviewLeaveOut, reviewFiles, reviewBack}
+		if !item.included {
+			actions[0] = reviewPutBack
+		}
+		menu := promptui.Select{
+			Label:        fmt.Sprintf("%.7s", item.commit.GetSHA()),
+			Items:        actions,
+			HideSelected: true,
+			Stdin:        stdin,
+			Stdout:       &bellSkipper{stdout},
+		}
+		_, action, err := menu.Run()
+		if err != nil {
+			return nil, err
+		}
+		switch action {
+		case reviewLeaveOut, reviewPutBack:
+			item.included = !item.included
+		case reviewFiles:
+			if item.files == nil {
+				files, err := source.CommitFiles(ctx, item.commit.GetSHA())
+				if err != nil {
+					return nil, fmt.Errorf("failed to retrieve files of commit %s: %w", item.commit.GetSHA(), err)
+				}
+				item.files = append([]string{}, files...) // non-nil, as looked up
+			}
+		}
+	}
+
+	kept := *comparison
+	kept.Commits = nil
+	for _, item := range items {
+		if item.included {
+			kept.Commits = append(kept.Commits, *item.commit)
+		}
+	}
+	return &kept, nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mroth/bump/internal/bumptest"
+)
+
+func TestReviewItemDetails(t *testing.T) {
+	c := bumptest.CommitsComparisons["sample"].Commits[3]
+	item := &reviewItem{commit: &c, included: true}
+	if got := item.Details(); !strings.Contains(got, c.GetCommit().GetMessage()) || strings.Contains(got, "files changed") {
+		t.Errorf("Details() before files looked up = %q", got)
+	}
+
+	for i := range 12 {
+		item.files = append(item.files, fmt.Sprintf("auth/auth%d_test.go", i))
+	}
+	got := item.Details()
+	for _, want := range []string{"12 files changed:\n", "  auth/auth9_test.go\n", "  ...and 2 more\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Details() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "auth10_test.go") {
+		t.Errorf("Details() = %q, want files past %d summed up", got, maxReviewFiles)
+	}
+}
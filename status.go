@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/plan"
+	"github.com/mroth/bump/release"
+)
+
+// defaultJobs is the number of repositories checked at once for status.
+const defaultJobs = 8
+
+// repoStatus is how far a repository has moved on since its latest release.
+type repoStatus struct {
+	Owner, Repo string
+	Latest      *github.RepositoryRelease
+	Version     *semver.Version           // of the latest release
+	Comparison  *github.CommitsComparison // commits since the latest release
+	Choices     []plan.Choice             // candidates for the next version
+	Next        plan.Choice               // suggested next version
+	Err         error                     // why the status is unknown
+}
+
+// unreleased returns the number of commits since the latest release.
+func (s repoStatus) unreleased() int {
+	if s.Comparison == nil {
+		return 0
+	}
+	return len(s.Comparison.Commits)
+}
+
+// getStatus works out the status of the repository source reads from, for a
+// release drafted from target at time now.
+func getStatus(ctx context.Context, source release.Source, cfg *Config, target string, now time.Time) (repoStatus, error) {
+	var s repoStatus
+	mode, err := plan.ParseTagMode(cfg.TagMode)
+	if err != nil {
+		return s, err
+	}
+	latest, err := release.Previous(ctx, source, target, mode)
+	if err != nil {
+		return s, err
+	}
+	version, err := mode.Parse(latest.GetTagName())
+	if err != nil {
+		latest, version, err = newestBase(ctx, source, latest.GetTagName(), mode)
+		if err != nil {
+			return s, err
+		}
+	}
+	comparison, err := source.Compare(ctx, latest.GetTagName(), target)
+	if err != nil {
+		return s, err
+	}
+	scheme, err := plan.ParseScheme(cfg.Scheme)
+	if err != nil {
+		return s, err
+	}
+	s.Latest, s.Version, s.Comparison = latest, version, comparison
+	s.Choices = scheme.Choices(version, now)
+	s.Next = plan.Suggest(s.Choices, comparison)
+	return s, nil
+}
+
+// newestBase is chooseBase without the prompt, as status and next are not
+// interactive: it takes the newest release before latest whose tag can be
+// parsed as a version, logging those skipped.
+func newestBase(ctx context.Context, source release.Source, latest string, mode plan.TagMode) (*github.RepositoryRelease, *semver.Version, error) {
+	releases, err := source.Releases(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	bases, skipped := plan.Bases(releases, latest, mode)
+	for _, s := range skipped {
+		logVerbose("skipping release %s: %s", s.Tag, s.Reason)
+	}
+	if len(bases) == 0 {
+		return nil, nil, fmt.Errorf("no release with a %s semantic version tag found", mode)
+	}
+	return bases[0].Release, bases[0].Version, nil
+}
+
+// errUnreleased is returned by runStatus when there are changes since the
+// latest release, so the program exits with exitUnreleased.
+var errUnreleased = errors.New("unreleased changes")
+
+// exitUnreleased is the exit code of status when there are unreleased changes,
+// distinct from the exit code 1 of errors.
+const exitUnreleased = 3
+
+// runStatus shows how far repositories have moved on since their latest
+// release, without drafting anything unless asked to: many repositories if
+// opts.Org or opts.Repos is set, otherwise owner/repo or the local repo.
+func runStatus(ctx context.Context, owner, repo string, opts Options, env *environment) error {
+	if opts.Org != "" && opts.Repos != "" {
+		return errors.New("--org and --repos cannot be used together")
+	}
+	if opts.Org != "" || opts.Repos != "" {
+		return runStatusBatch(ctx, opts, env)
+	}
+
+	ws, err := openWorkspace(owner, repo, opts.Offline, true, opts, env)
+	if err != nil {
+		return err
+	}
+	s, err := getStatus(ctx, ws.source(opts, env), ws.Config, ws.Target, env.Now())
+	if err != nil {
+		return err
+	}
+	published := s.Latest.GetPublishedAt().Time
+	fmt.Fprintf(env.Stdout, "🌻 Latest release of %v (published %v, %s)\n",
+		boldStyler(fmt.Sprintf("%v/%v: %v", ws.Owner, ws.Repo, plan.VersionString(s.Version))),
+		published.Format("2006 Jan 2"), daysAgo(published, env.Now()),
+	)
+	if s.unreleased() == 0 {
+		fmt.Fprintln(env.Stdout, "✅ No changes since the latest release.")
+		return nil
+	}
+	fmt.Fprintf(env.Stdout, "📦 %d unreleased commits, suggesting %v\n",
+		s.unreleased(), boldStyler(fmt.Sprintf("v%s (%s)", plan.VersionString(&s.Next.Version), s.Next.Name)))
+	fmt.Fprintf(env.Stdout, "🔗 %s\n", s.Comparison.GetHTMLURL())
+	return errUnreleased
+}
+
+// daysAgo describes how long before now t was, in days.
+func daysAgo(t, now time.Time) string {
+	switch days := int(now.Sub(t).Hours() / 24); days {
+	case 0:
+		return "today"
+	case 1:
+		return "1 day ago"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
+
+// runStatusBatch shows the status of many repositories at once, those of
+// the organization opts.Org or listed in the file opts.Repos, then offers to
+// draft releases for those with unreleased changes one after another.
+func runStatusBatch(ctx context.Context, opts Options, env *environment) error {
+	if opts.Offline {
+		return errors.New("--offline cannot be used with --org or --repos")
+	}
+	if opts.ChangelogFile != "" {
+		// the file would be updated in the local repo, not the ones drafted
+		return errors.New("--changelog-file cannot be used with --org or --repos")
+	}
+	if opts.Jobs < 0 {
+		return usageError("--jobs must be a positive number, or 0 for the default")
+	}
+
+	var (
+		repos []string // as owner/repo
+		err   error
+	)
+	if opts.Org != "" {
+		var names []string
+		names, err = env.OrgRepos(ctx, opts.Org)
+		for _, name := range names {
+			repos = append(repos, opts.Org+"/"+name)
+		}
+	} else {
+		repos, err = readRepoList(filepath.Join(env.Dir, opts.Repos))
+	}
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return errors.New("no repositories to check")
+	}
+
+	start := time.Now()
+	statuses := fetchStatuses(ctx, env, repos, cmp.Or(opts.Jobs, defaultJobs))
+	timeTrack(start, fmt.Sprintf("status of %d repos", len(repos)))
+	printStatusTable(env.Stdout, statuses)
+
+	var pending []repoStatus
+	for _, s := range statuses {
+		if s.Err == nil && s.unreleased() > 0 {
+			pending = append(pending, s)
+		}
+	}
+	if len(pending) == 0 {
+		fmt.Fprintln(env.Stdout, "\n✅ Every repository is up to date with its latest release.")
+		return nil
+	}
+	for len(pending) > 0 {
+		fmt.Fprintln(env.Stdout)
+		i, err := promptStatusRepo(pending, env.Stdin, env.Stderr)
+		if err != nil || i == -1 {
+			return err
+		}
+		s := pending[i]
+		pending = append(pending[:i:i], pending[i+1:]...)
+		if err := run(ctx, s.Owner, s.Repo, opts, env); err != nil {
+			fmt.Fprintf(env.Stdout, "⚠️  %s/%s: %v\n", s.Owner, s.Repo, err)
+		}
+	}
+	return nil
+}
+
+// fetchStatuses gets the status of each of repos, given as owner/repo, with a
+// pool of jobs workers. Statuses are returned in the same order as repos.
+func fetchStatuses(ctx context.Context, env *environment, repos []string, jobs int) []repoStatus {
+	statuses := make([]repoStatus, len(repos))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(jobs, len(repos)) {
+		wg.Go(func() {
+			for i := range work {
+				owner, repo, _ := strings.Cut(repos[i], "/")
+				s, err := getStatus(ctx, env.GitHub(owner, repo), &Config{}, "", env.Now())
+				s.Owner, s.Repo, s.Err = owner, repo, err
+				statuses[i] = s
+			}
+		})
+	}
+	for i := range repos {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	return statuses
+}
+
+// printStatusTable writes the statuses as a table, one repository per row.
+func printStatusTable(w io.Writer, statuses []repoStatus) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPOSITORY\tLATEST\tRELEASED\tUNRELEASED\tSUGGESTED")
+	for _, s := range statuses {
+		name := s.Owner + "/" + s.Repo
+		if s.Err != nil {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t%s\n", name, faintStyler("error: "+s.Err.Error()))
+			continue
+		}
+		next := "-"
+		if s.unreleased() > 0 {
+			next = fmt.Sprintf("v%s (%s)", plan.VersionString(&s.Next.Version), s.Next.Name)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", name, s.Latest.GetTagName(),
+			s.Latest.GetPublishedAt().Format("2006-01-02"), s.unreleased(), next)
+	}
+	tw.Flush()
+}
+
+// readRepoList reads the repositories listed in the file at path, one
+// owner/repo per line. Blank lines and lines starting with # are ignored.
+func readRepoList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var repos []string
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		owner, repo, ok := strings.Cut(s, "/")
+		if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return nil, fmt.Errorf("%s:%d: %q is not owner/repo", path, line, s)
+		}
+		repos = append(repos, s)
+	}
+	return repos, scanner.Err()
+}
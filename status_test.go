@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/internal/bumptest"
+	"github.com/mroth/bump/release"
+)
+
+func TestStatus(t *testing.T) {
+	t.Run("unreleased", func(t *testing.T) {
+		r := newTestRun(t)
+		env := r.env("")
+		env.Now = func() time.Time { return bumptest.Now.Add(72 * time.Hour) }
+		err := runStatus(context.Background(), "", "", Options{}, env)
+		if !errors.Is(err, errUnreleased) {
+			t.Fatalf("runStatus() = %v, want errUnreleased", err)
+		}
+		for _, want := range []string{
+			"owner/repo: 1.0.0",
+			"(published 2025 Jul 22, 3 days ago)",
+			"12 unreleased commits",
+			"v1.1.0 (minor)",
+			bumptest.CommitsComparisons["sample"].GetHTMLURL(),
+		} {
+			if !strings.Contains(r.stdout.String(), want) {
+				t.Errorf("output missing %q, got:\n%s", want, r.stdout.String())
+			}
+		}
+		if len(r.opened) != 0 || len(r.fake.Created) != 0 {
+			t.Errorf("status drafted a release")
+		}
+	})
+
+	t.Run("non-semver latest release", func(t *testing.T) {
+		r := newTestRun(t)
+		r.fake.Releases = append([]*github.RepositoryRelease{{
+			TagName:     github.String("nightly"),
+			PublishedAt: &github.Timestamp{Time: bumptest.Now.Add(time.Hour)},
+		}}, r.fake.Releases...)
+		err := runStatus(context.Background(), "", "", Options{}, r.env(""))
+		if !errors.Is(err, errUnreleased) {
+			t.Fatalf("runStatus() = %v, want errUnreleased", err)
+		}
+		for _, want := range []string{"owner/repo: 1.0.0", "v1.1.0 (minor)"} {
+			if !strings.Contains(r.stdout.String(), want) {
+				t.Errorf("output missing %q, got:\n%s", want, r.stdout.String())
+			}
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		r := newTestRun(t)
+		r.fake.Comparisons["v1.0.0...HEAD"] = &github.CommitsComparison{}
+		if err := runStatus(context.Background(), "", "", Options{}, r.env("")); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(r.stdout.String(), "No changes since the latest release") {
+			t.Errorf("got output:\n%s", r.stdout.String())
+		}
+	})
+}
+
+func TestStatusBatch(t *testing.T) {
+	// api has unreleased changes, web is up to date, and gone has no releases
+	fakes := map[string]*bumptest.GitHub{}
+	for _, name := range []string{"api", "web", "gone"} {
+		fakes[name] = bumptest.NewGitHub(t)
+	}
+	fakes["api"].Releases = []*github.RepositoryRelease{{
+		TagName:     github.String("v1.0.0"),
+		PublishedAt: &github.Timestamp{Time: bumptest.Now},
+	}}
+	fakes["api"].Comparisons["v1.0.0...HEAD"] = bumptest.CommitsComparisons["sample"]
+	fakes["web"].Releases = []*github.RepositoryRelease{{
+		TagName:     github.String("v2.3.0"),
+		PublishedAt: &github.Timestamp{Time: bumptest.Now},
+	}}
+	fakes["web"].Comparisons["v2.3.0...HEAD"] = &github.CommitsComparison{}
+	fakes["api"].Orgs["acme"] = []*github.Repository{
+		{Name: github.String("api")}, {Name: github.String("gone")}, {Name: github.String("web")},
+	}
+
+	var stdout strings.Builder
+	env := &environment{
+		Dir:    t.TempDir(),
+		Stdin:  io.NopCloser(keystrokes{strings.NewReader("\nj\n")}), // api, then minor
+		Stdout: &stdout,
+		Stderr: io.Discard,
+		GitHub: func(owner, repo string) release.Source {
+			return release.NewGitHubSource(fakes[repo].APIClient(), owner, repo)
+		},
+		OrgRepos: func(ctx context.Context, org string) ([]string, error) {
+			return release.OrgRepos(ctx, fakes["api"].APIClient(), org)
+		},
+		Now: func() time.Time { return bumptest.Now },
+	}
+
+	if err := runStatus(context.Background(), "", "", Options{Org: "acme", NoOpen: true, Jobs: 2}, env); err != nil {
+		t.Fatal(err)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"REPOSITORY  LATEST  RELEASED    UNRELEASED  SUGGESTED\n",
+		"acme/api    v1.0.0  2025-07-22  12          v1.1.0 (minor)\n",
+		"acme/gone   -       -           -           ",
+		"acme/web    v2.3.0  2025-07-22  0           -\n",
+		"To draft release, visit: https://github.com/acme/api/releases/new?tag=v1.1.0",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestStatusBatchJobs(t *testing.T) {
+	env := &environment{Dir: t.TempDir()}
+	err := runStatus(context.Background(), "", "", Options{Org: "acme", Jobs: -1}, env)
+	if !errors.Is(err, errUsage) {
+		t.Errorf("runStatus() with --jobs -1 error = %v, want errUsage", err)
+	}
+	if strings.Contains(err.Error(), "usage") {
+		t.Errorf("runStatus() with --jobs -1 error = %q, mentions usage", err)
+	}
+}
+
+func TestStatusBatchChangelogFile(t *testing.T) {
+	env := &environment{Dir: t.TempDir()}
+	err := runStatus(context.Background(), "", "", Options{Repos: "repos.txt", ChangelogFile: "CHANGELOG.md"}, env)
+	if err == nil || !strings.Contains(err.Error(), "--changelog-file") {
+		t.Errorf("runStatus() with --changelog-file error = %v, want refusal", err)
+	}
+}
+
+func TestReadRepoList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repos.txt")
+	if err := os.WriteFile(path, []byte("# services\nacme/api\n\n  acme/web  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readRepoList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "acme/api,acme/web" {
+		t.Errorf("readRepoList() = %v", got)
+	}
+
+	if err := os.WriteFile(path, []byte("acme/api\nacme\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readRepoList(path); err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("readRepoList() error = %v, want one for line 2", err)
+	}
+}
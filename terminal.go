@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mroth/bump/changelog"
+	"golang.org/x/term"
+)
+
+// colorOutput is whether output is styled with colors and other ANSI escape
+// sequences, decided once for the process by main with colorEnabled.
+var colorOutput bool
+
+// colorEnabled reports whether output to w should be styled: only when w is a
+// terminal, and NO_COLOR is not set (https://no-color.org). Output which is
+// not to a terminal, such as to a pipe, is left plain.
+func colorEnabled(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd())) && os.Getenv("NO_COLOR") == ""
+}
+
+// colored wraps a promptui.Styler to leave text plain unless colorOutput is
+// set.
+func colored(style func(any) string) func(any) string {
+	return func(v any) string {
+		if !colorOutput {
+			return fmt.Sprint(v)
+		}
+		return style(v)
+	}
+}
+
+// screenOptions returns the options for rendering the changelog to w, fitting
+// it to the terminal w is, with links, and colors if colorOutput is set.
+// Output which is not to a terminal is left plain.
+func screenOptions(w io.Writer, links *changelog.Links) changelog.Options {
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return changelog.Options{}
+	}
+	opts := changelog.Options{Links: links, Color: colorOutput}
+	if width, height, err := term.GetSize(int(f.Fd())); err == nil {
+		opts.Width, opts.Height = width, height
+	}
+	return opts
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+	defer pw.Close()
+	if colorEnabled(pw) {
+		t.Error("colorEnabled() for a pipe = true, want false")
+	}
+	if colorEnabled(&strings.Builder{}) {
+		t.Error("colorEnabled() for a buffer = true, want false")
+	}
+}
+
+func TestPipedOutputPlain(t *testing.T) {
+	t.Cleanup(func() { colorOutput = false })
+	for _, color := range []bool{false, true} {
+		colorOutput = color
+		r := newTestRun(t)
+		if err := r.run(Options{DryRun: true, SkipChecks: true}, "j\n"); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(r.stdout.String(), "\x1b["); got != color {
+			t.Errorf("with colorOutput %v, output has escape sequences %v:\n%q", color, got, r.stdout.String())
+		}
+	}
+}
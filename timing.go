@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/mroth/bump/changelog"
+	"github.com/mroth/bump/release"
+)
+
+// timedSource wraps a release.Source to log timing info for each call, since
+// those are where all the network requests happen.
+type timedSource struct {
+	release.Source
+}
+
+func (s timedSource) LatestRelease(ctx context.Context) (*github.RepositoryRelease, error) {
+	defer timeTrack(time.Now(), "Source.LatestRelease()")
+	return s.Source.LatestRelease(ctx)
+}
+
+func (s timedSource) Releases(ctx context.Context) ([]*github.RepositoryRelease, error) {
+	defer timeTrack(time.Now(), "Source.Releases()")
+	return s.Source.Releases(ctx)
+}
+
+func (s timedSource) Tags(ctx context.Context) ([]string, error) {
+	defer timeTrack(time.Now(), "Source.Tags()")
+	return s.Source.Tags(ctx)
+}
+
+func (s timedSource) Compare(ctx context.Context, base, head string) (*github.CommitsComparison, error) {
+	defer timeTrack(time.Now(), "Source.Compare()")
+	return s.Source.Compare(ctx, base, head)
+}
+
+func (s timedSource) RemoteState(ctx context.Context, target string) (release.RemoteState, error) {
+	defer timeTrack(time.Now(), "Source.RemoteState()")
+	return s.Source.RemoteState(ctx, target)
+}
+
+func (s timedSource) CommitFiles(ctx context.Context, sha string) ([]string, error) {
+	defer timeTrack(time.Now(), "Source.CommitFiles()")
+	return s.Source.CommitFiles(ctx, sha)
+}
+
+func (s timedSource) HasContributed(ctx context.Context, ref string, author changelog.Author) (bool, error) {
+	defer timeTrack(time.Now(), "Source.HasContributed()")
+	return s.Source.HasContributed(ctx, ref, author)
+}
+
+func (s timedSource) CreateRelease(ctx context.Context, r *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	defer timeTrack(time.Now(), "Source.CreateRelease()")
+	return s.Source.CreateRelease(ctx, r)
+}
@@ -0,0 +1,28 @@
+package versionfile_test
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/mroth/bump/versionfile"
+)
+
+func ExampleFile_Updater() {
+	before := []byte("[package]\nname = \"bump\"\nversion = \"0.3.1\"\n")
+
+	u, err := versionfile.File{Path: "Cargo.toml"}.Updater()
+	if err != nil {
+		panic(err)
+	}
+	after, err := u.Update(before, semver.MustParse("0.3.1"), semver.MustParse("0.4.0"))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(versionfile.Diff("Cargo.toml", before, after))
+	// Output:
+	// --- a/Cargo.toml
+	// +++ b/Cargo.toml
+	// @@ -3 +3 @@
+	// -version = "0.3.1"
+	// +version = "0.4.0"
+}
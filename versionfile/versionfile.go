@@ -0,0 +1,155 @@
+// Package versionfile updates the version number recorded in files stored in
+// version control alongside a project's source, such as package.json or
+// Cargo.toml, so they can be kept in step with the release tags.
+package versionfile
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/mroth/bump/plan"
+)
+
+// Updater rewrites the previous version recorded in the contents of a file
+// with the next version.
+type Updater interface {
+	Update(content []byte, prev, next *semver.Version) ([]byte, error)
+}
+
+// ErrVersionNotFound is returned by an Updater when the previous version does
+// not appear where it was expected in the file.
+var ErrVersionNotFound = errors.New("previous version not found")
+
+// Pattern is an Updater matching a regular expression in which the
+// placeholder {version} stands for the previous version, with or without a
+// leading "v". Only the first match is rewritten, and a leading "v" is kept.
+type Pattern string
+
+// Update implements Updater.
+func (p Pattern) Update(content []byte, prev, next *semver.Version) ([]byte, error) {
+	if !strings.Contains(string(p), "{version}") {
+		return nil, fmt.Errorf("invalid pattern %q: missing {version} placeholder", p)
+	}
+	expr := strings.Replace(string(p), "{version}", `(?P<bumpversion>v?`+regexp.QuoteMeta(plan.VersionString(prev))+`)`, 1)
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	m := re.FindSubmatchIndex(content)
+	if m == nil {
+		return nil, ErrVersionNotFound
+	}
+	// the pattern may have groups of its own before the placeholder
+	i := re.SubexpIndex("bumpversion")
+	start, end := m[2*i], m[2*i+1]
+	replacement := plan.VersionString(next)
+	if content[start] == 'v' {
+		replacement = "v" + replacement
+	}
+
+	var out []byte
+	out = append(out, content[:start]...)
+	out = append(out, replacement...)
+	out = append(out, content[end:]...)
+	return out, nil
+}
+
+// formats are the Updaters known by name.
+var formats = map[string]Updater{
+	"npm":       Pattern(`(?m)^\s*"version"\s*:\s*"{version}"`),
+	"cargo":     Pattern(`(?m)^version\s*=\s*"{version}"`),
+	"pyproject": Pattern(`(?m)^version\s*=\s*"{version}"`),
+	"plain":     Pattern(`\A\s*{version}\s*\z`),
+	"go":        Pattern(`\bVersion\s*(?:string\s*)?=\s*"{version}"`),
+}
+
+// Register makes an Updater available under the format name, replacing any
+// Updater previously registered with that name.
+func Register(name string, u Updater) {
+	formats[name] = u
+}
+
+// Formats returns the names of all registered formats, sorted.
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatFor infers the format of the file at path from its name.
+func formatFor(path string) string {
+	switch base := filepath.Base(path); {
+	case base == "package.json":
+		return "npm"
+	case base == "Cargo.toml":
+		return "cargo"
+	case base == "pyproject.toml":
+		return "pyproject"
+	case base == "VERSION", base == "VERSION.txt":
+		return "plain"
+	case filepath.Ext(base) == ".go":
+		return "go"
+	default:
+		return ""
+	}
+}
+
+// File is a file which records the project version, as configured for a
+// repository.
+type File struct {
+	// Path of the file, relative to the root of the repository.
+	Path string `json:"path"`
+	// Format names the Updater to use. If empty, it is inferred from the
+	// name of the file.
+	Format string `json:"format,omitempty"`
+	// Pattern, if set, is used as the Updater instead of a named format.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// Updater returns the Updater configured for the file.
+func (f File) Updater() (Updater, error) {
+	if f.Pattern != "" {
+		return Pattern(f.Pattern), nil
+	}
+	format := f.Format
+	if format == "" {
+		if format = formatFor(f.Path); format == "" {
+			return nil, fmt.Errorf("%s: unknown format, set one of %s or a pattern",
+				f.Path, strings.Join(Formats(), ", "))
+		}
+	}
+	u, ok := formats[format]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown format %q (want one of %s)",
+			f.Path, format, strings.Join(Formats(), ", "))
+	}
+	return u, nil
+}
+
+// Diff returns the lines which differ between before and after, as a
+// minimal unified diff of the file at path. Updaters only ever rewrite
+// within lines, so lines are compared one to one.
+func Diff(path string, before, after []byte) string {
+	oldLines := strings.Split(string(before), "\n")
+	newLines := strings.Split(string(after), "\n")
+	if len(oldLines) != len(newLines) {
+		return fmt.Sprintf("--- a/%s\n+++ b/%s\n(line count changed)\n", path, path)
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- a/%s\n+++ b/%s\n", path, path)
+	for i := range oldLines {
+		if oldLines[i] != newLines[i] {
+			fmt.Fprintf(&buf, "@@ -%d +%d @@\n-%s\n+%s\n", i+1, i+1, oldLines[i], newLines[i])
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,89 @@
+package versionfile
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestUpdate(t *testing.T) {
+	prev, next := semver.MustParse("1.4.2"), semver.MustParse("1.5.0")
+	testCases := []struct {
+		file File
+		in   string
+		want string
+	}{
+		{
+			file: File{Path: "package.json"},
+			in:   "{\n  \"name\": \"x\",\n  \"version\": \"1.4.2\",\n  \"dependencies\": {\"y\": \"1.4.2\"}\n}\n",
+			want: "{\n  \"name\": \"x\",\n  \"version\": \"1.5.0\",\n  \"dependencies\": {\"y\": \"1.4.2\"}\n}\n",
+		},
+		{
+			file: File{Path: "Cargo.toml"},
+			in:   "[package]\nname = \"x\"\nversion = \"1.4.2\"\n\n[dependencies]\ny = { version = \"1.4.2\" }\n",
+			want: "[package]\nname = \"x\"\nversion = \"1.5.0\"\n\n[dependencies]\ny = { version = \"1.4.2\" }\n",
+		},
+		{
+			file: File{Path: "sub/pyproject.toml"},
+			in:   "[project]\nname = \"x\"\nversion = \"1.4.2\"\n",
+			want: "[project]\nname = \"x\"\nversion = \"1.5.0\"\n",
+		},
+		{
+			file: File{Path: "VERSION"},
+			in:   "v1.4.2\n",
+			want: "v1.5.0\n",
+		},
+		{
+			file: File{Path: "version.go"},
+			in:   "package main\n\nconst Version = \"1.4.2\"\n",
+			want: "package main\n\nconst Version = \"1.5.0\"\n",
+		},
+		{
+			file: File{Path: "chart/Chart.yaml", Pattern: `(?m)^appVersion: {version}$`},
+			in:   "version: 0.3.0\nappVersion: 1.4.2\n",
+			want: "version: 0.3.0\nappVersion: 1.5.0\n",
+		},
+		{
+			file: File{Path: "build.gradle", Pattern: `(version|ver) = '{version}'`},
+			in:   "group = 'x'\nversion = '1.4.2'\n",
+			want: "group = 'x'\nversion = '1.5.0'\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.file.Path, func(t *testing.T) {
+			u, err := tc.file.Updater()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := u.Update([]byte(tc.in), prev, next)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateErrors(t *testing.T) {
+	prev, next := semver.MustParse("1.4.2"), semver.MustParse("1.5.0")
+
+	u, _ := File{Path: "VERSION"}.Updater()
+	if _, err := u.Update([]byte("1.4.1\n"), prev, next); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("stale version: want ErrVersionNotFound, got %v", err)
+	}
+	if _, err := (File{Path: "setup.cfg"}).Updater(); err == nil {
+		t.Error("unknown file name: want error, got nil")
+	}
+	if _, err := (File{Path: "VERSION", Format: "nope"}).Updater(); err == nil {
+		t.Error("unknown format: want error, got nil")
+	}
+	if _, err := Pattern(`version: 1`).Update([]byte("version: 1"), prev, next); err == nil {
+		t.Error("pattern without placeholder: want error, got nil")
+	}
+	if _, err := Pattern(`version: (1\.4\.2)`).Update([]byte("version: 1.4.2"), prev, next); err == nil {
+		t.Error("pattern with a group but no placeholder: want error, got nil")
+	}
+}